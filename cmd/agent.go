@@ -1,18 +1,36 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/VintageOps/structogqlgen/pkg/config"
 	"github.com/VintageOps/structogqlgen/pkg/conversion"
+	"github.com/VintageOps/structogqlgen/pkg/gqlgen"
 	"github.com/VintageOps/structogqlgen/pkg/load"
+	"github.com/VintageOps/structogqlgen/pkg/output"
 	"github.com/urfave/cli/v2"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 type cmdOptions struct {
-	fNameContStruct string
-	printOpts       conversion.PrettyPrintOptions
+	fNameContStruct   string
+	convertOpts       conversion.ConvertOptions
+	printOpts         conversion.PrettyPrintOptions
+	diagnosticsFormat string
+	strict            bool
+	verifyBinding     bool
+	out               string
+	split             string
+	merge             bool
+	gqlgenConfig      string
+	gqlgenScalars     map[string]string
+	scalarsGoOut      string
+	scalarsGoPackage  string
+	configPath        string
+	target            string
 }
 
 func Execute() {
@@ -22,59 +40,19 @@ func Execute() {
 		Usage:                "Converts Golang structs into GraphQL types that are readily usable with the popular GraphQL framework, gqlgen",
 		EnableBashCompletion: true,
 		HideHelpCommand:      true,
+		// The repeatable flags take a single value each, e.g. a directive definition may contain commas
+		DisableSliceFlagSeparator: true,
 		Authors: []*cli.Author{
 			{Name: "VintageOps"},
 		},
 		Description: "StructsToGqlGenTypes is a tool that helps to automatically converts Golang structs into GraphQL types that are readily usable with the popular GraphQL framework, gqlgen.\n" +
 			"It aims to reduce the boilerplate code required to define GraphQL schemas manually, thus accelerating the development of GraphQL APIs in Go projects.",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:        "src",
-				Usage:       "`SRC_PATH` is the required path to the source file containing the structs to import (required)",
-				Destination: &opts.fNameContStruct,
-				Required:    true,
-				Aliases:     []string{"s"},
-			},
-			&cli.BoolFlag{
-				Name:        "use-json-tags",
-				Usage:       "Use JSON Tag as field name when available. If this is selected and a field has no Json tag, then the field name will be used.",
-				Destination: &opts.printOpts.UseJsonTags,
-				Aliases:     []string{"j"},
-			},
-			&cli.StringFlag{
-				Name:        "use-custom-tags",
-				Usage:       "Specify a custom tag to use as field name. Specifying this takes precedence over JSON tags. If specifed and a field does not have this tag, the field name will be used",
-				Destination: &opts.printOpts.UseCustomTags,
-				Aliases:     []string{"c"},
-			},
-			&cli.StringFlag{
-				Name:    "tags-value-ignored",
-				Usage:   "Specify a tag value that signal to ignore Field with tag having this value. When using json tags with use-json-tags option, if this not specified, it is automatically set to '-'",
-				Aliases: []string{"i"},
-				Action: func(context *cli.Context, s string) error {
-					// workaround as Destination: opts.printOpts.TagFieldToIgnore does not set the provided value
-					opts.printOpts.TagFieldToIgnore = &s
-					return nil
-				},
-			},
-			&cli.StringFlag{
-				Name:    "required-tags",
-				Usage:   "If there is a tag that make a field required, specified that tag using the format `key=value`. e.g. validate=required",
-				Aliases: []string{"r"},
-				Action: func(context *cli.Context, required string) error {
-					parts := strings.SplitN(required, "=", 2)
-					if len(parts) != 2 {
-						return fmt.Errorf("invalid format for required-tags, expected key=value")
-					}
-					opts.printOpts.RequireTags.Key = parts[0]
-					opts.printOpts.RequireTags.Val = parts[1]
-					return nil
-				},
-			},
-		},
+		// The flags of the generate command are kept on the main command, which runs generate when no command is given
+		Flags:    generateFlags(&opts),
+		Commands: []*cli.Command{generateCommand(), checkCommand(), diffCommand(), verifyCommand(), listCommand(), initCommand()},
 	}
 	app.Action = func(c *cli.Context) error {
-		return printStructsAsGraphqlTypes(&opts)
+		return generate(&opts)
 	}
 
 	err := app.Run(os.Args)
@@ -83,25 +61,509 @@ func Execute() {
 	}
 }
 
-// printStructsAsGraphqlTypes prints the GraphQL type definitions corresponding to the structs found in the provided source file.
-// - load.GetStructsFromSourceFile function to find all structs defined in the source file.
-// - buildTypeDefinitions function to build the GraphQL type definitions for each struct.
-// - conversion.GqlPrettyPrint function to pretty print the GraphQL type definitions and prints the result.
+// generateCommand returns the generate command, which generates the schema of the structs.
+func generateCommand() *cli.Command {
+	var opts cmdOptions
+	return &cli.Command{
+		Name:  "generate",
+		Usage: "Generates the GraphQL schema of the structs, the default command",
+		Description: "Prints the GraphQL schema of the structs found in --src, or writes it into --out.\n" +
+			"Without --src, it generates every target of the configuration file, or the one selected with --target.",
+		Flags: generateFlags(&opts),
+		Action: func(c *cli.Context) error {
+			return generate(&opts)
+		},
+	}
+}
+
+// generate generates the schema of each target, see resolveTargets.
+func generate(opts *cmdOptions) error {
+	targets, err := resolveTargets(opts)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := printStructsAsGraphqlTypes(&target.opts); err != nil {
+			return target.wrapErr(err)
+		}
+	}
+	return nil
+}
+
+// cmdTarget represents a schema target to run a command on.
+type cmdTarget struct {
+	name string // name is the name of the target in the configuration file, empty when the target comes from the flags
+	opts cmdOptions
+}
+
+// wrapErr returns the error err prefixed with the name of the target, if any.
+func (target *cmdTarget) wrapErr(err error) error {
+	if target.name == "" {
+		return err
+	}
+	return fmt.Errorf("target %s: %v", target.name, err)
+}
+
+// resolveTargets returns the targets to run a command on.
+// When --src is set, the only target is described by the flags. Otherwise, the targets are the ones of the configuration
+// file set with --config, or found in the working directory or its closest parent directory, filtered with --target.
+// The diagnostics format is always the one of the flags.
+func resolveTargets(opts *cmdOptions) ([]cmdTarget, error) {
+	if opts.fNameContStruct != "" {
+		return []cmdTarget{{opts: *opts}}, nil
+	}
+
+	configPath := opts.configPath
+	if configPath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		configPath, err = config.Find(wd)
+		if err != nil {
+			return nil, err
+		}
+		if configPath == "" {
+			return nil, fmt.Errorf("required flag \"src\" not set, and no %s configuration file found", config.FileName)
+		}
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	selected, err := cfg.SelectTargets(opts.target)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]cmdTarget, 0, len(selected))
+	for _, target := range selected {
+		// The options were validated by config.Load
+		printOpts, _ := target.PrettyPrintOptions()
+		targets = append(targets, cmdTarget{
+			name: target.Name,
+			opts: cmdOptions{
+				fNameContStruct:   target.Src,
+				convertOpts:       target.ConvertOptions(),
+				printOpts:         printOpts,
+				diagnosticsFormat: opts.diagnosticsFormat,
+				strict:            target.Strict,
+				out:               target.Out,
+				split:             target.Split,
+				merge:             target.Merge,
+				gqlgenConfig:      target.GqlgenConfig,
+				gqlgenScalars:     target.GqlgenScalars,
+				scalarsGoOut:      target.ScalarsGoOut,
+				scalarsGoPackage:  target.ScalarsGoPackage,
+			},
+		})
+	}
+	return targets, nil
+}
+
+// printStructsAsGraphqlTypes prints the GraphQL type definitions corresponding to the structs found in the provided source path,
+// or writes them into the output path if one is provided. The marshalers of the custom scalars are then generated, and
+// the models section of the gqlgen configuration file updated, if their paths are provided.
 func printStructsAsGraphqlTypes(opts *cmdOptions) error {
-	structsFound, err := load.GetStructsFromSourceFile(opts.fNameContStruct)
+	schema, err := buildSchema(opts)
 	if err != nil {
 		return err
 	}
 
-	gqlGenTypes, err := conversion.BuildGqlTypes(structsFound)
+	if opts.out == "" {
+		fmt.Println(schema.String())
+	} else if err := writeSchema(schema, opts); err != nil {
+		return err
+	}
+	scalarModels := opts.gqlgenScalars
+	if opts.scalarsGoOut != "" {
+		if scalarModels, err = writeScalarMarshalers(schema, opts); err != nil {
+			return err
+		}
+	}
+	if opts.gqlgenConfig != "" {
+		return writeGqlgenModels(schema, scalarModels, opts)
+	}
+	return nil
+}
+
+// writeScalarMarshalers writes the marshalers of the custom scalars of the schema into the Go file, see
+// gqlgen.ScalarsSource, and prints the file written on stderr. It returns the scalar models of the options completed
+// with the scalars the file implements, bound to its package when they are not bound yet.
+func writeScalarMarshalers(schema *conversion.GqlSchema, opts *cmdOptions) (map[string]string, error) {
+	written, implemented, err := gqlgen.WriteScalarsFile(opts.scalarsGoOut, opts.scalarsGoPackage, schema.Scalars)
+	if err != nil {
+		return nil, err
+	}
+	if written {
+		fmt.Fprintln(os.Stderr, "written:", opts.scalarsGoOut)
+	}
+	if opts.gqlgenConfig == "" || len(implemented) == 0 {
+		return opts.gqlgenScalars, nil
+	}
+
+	importPath, err := load.ImportPath(filepath.Dir(opts.scalarsGoOut))
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind the scalars to their marshalers: %v", err)
+	}
+	scalarModels := make(map[string]string, len(implemented)+len(opts.gqlgenScalars))
+	for _, scalar := range implemented {
+		scalarModels[scalar] = importPath + "." + scalar
+	}
+	for scalar, goType := range opts.gqlgenScalars {
+		scalarModels[scalar] = goType
+	}
+	return scalarModels, nil
+}
+
+// writeGqlgenModels updates the models section of the gqlgen configuration file with the models of the schema, see
+// gqlgen.UpdateContent, and prints the file written and the scalars without any Go type on stderr.
+func writeGqlgenModels(schema *conversion.GqlSchema, scalarModels map[string]string, opts *cmdOptions) error {
+	models, unbound := gqlgen.Models(schema, scalarModels)
+	for _, scalar := range unbound {
+		fmt.Fprintf(os.Stderr, "warning: scalar %s has no Go type, set one with --gqlgen-scalar %s=TYPE\n", scalar, scalar)
+	}
+	written, err := gqlgen.UpdateFile(opts.gqlgenConfig, models)
 	if err != nil {
 		return err
 	}
+	if written {
+		fmt.Fprintln(os.Stderr, "written:", opts.gqlgenConfig)
+	}
+	return nil
+}
 
-	prettyPrint, err := conversion.GqlPrettyPrint(gqlGenTypes, &opts.printOpts)
+// buildSchema builds the GraphQL schema corresponding to the structs found in the provided source path.
+// - load.GetStructsFromPath function to find all structs defined in the source path.
+// - conversion.BuildGqlTypesWithOptions function to build the GraphQL type definitions for each struct, printing the diagnostics on stderr.
+// - conversion.ResolveGqlSchema function to resolve the GraphQL schema of the type definitions.
+// - conversion.ValidateGqlSchemaWithDefinitions function to validate the GraphQL schema, whose violations are errors in
+// strict mode, along with the hand-written definitions of the file it is merged into, with merge.
+// - conversion.VerifyGqlgenBinding function to verify gqlgen binds the fields to the Go structs, when verifying the binding.
+// - conversion.RelationsReport function to report the relations inferred from the foreign keys, when inferring them.
+// - conversion.ValidateFederation function to validate the keys of the entities, whose violations are errors in strict mode, with federation.
+func buildSchema(opts *cmdOptions) (*conversion.GqlSchema, error) {
+	structsFound, err := load.GetStructsFromPath(opts.fNameContStruct)
+	if err != nil {
+		return nil, err
+	}
+
+	gqlGenTypes, diagnostics, err := conversion.BuildGqlTypesWithOptions(structsFound, &opts.convertOpts)
+	if err != nil && !diagnostics.HasErrors() {
+		return nil, err
+	}
+	var schema *conversion.GqlSchema
+	if err == nil {
+		schema, err = conversion.ResolveGqlSchema(gqlGenTypes, &opts.printOpts)
+		if err != nil {
+			return nil, err
+		}
+		// With merge, the schema may reference the types and directives written by hand in the file it is merged into
+		var external *conversion.ExternalDefinitions
+		if opts.merge && isSingleFileOutput(opts.out, opts.split) {
+			if external, err = output.HandWrittenDefinitions(opts.out, schema); err != nil {
+				return nil, err
+			}
+		}
+		diagnostics = append(diagnostics, conversion.ValidateGqlSchemaWithDefinitions(schema, opts.strict, external)...)
+		if opts.verifyBinding {
+			diagnostics = append(diagnostics, conversion.VerifyGqlgenBinding(schema, true)...)
+		}
+		if opts.printOpts.Relations != "" {
+			diagnostics = append(diagnostics, conversion.RelationsReport(schema)...)
+		}
+		if opts.printOpts.Federation {
+			diagnostics = append(diagnostics, conversion.ValidateFederation(schema, opts.strict)...)
+		}
+	}
+
+	if printErr := printDiagnostics(diagnostics, opts.diagnosticsFormat); printErr != nil {
+		return nil, printErr
+	}
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("failed to build the schema, see the errors above")
+	}
+	return schema, nil
+}
+
+// isSingleFileOutput returns true if the schema is written into the single file out rather than into a directory.
+func isSingleFileOutput(out string, split string) bool {
+	info, err := os.Stat(out)
+	isDir := err == nil && info.IsDir()
+	return (split == "" || split == output.SplitNone) && !isDir && (strings.HasSuffix(out, ".graphqls") || strings.HasSuffix(out, ".graphql"))
+}
+
+// writeSchema writes the schema into the file out when it is not split and out is not a directory,
+// otherwise into files of the directory out, and prints the files written or removed on stderr.
+// With merge, the schema is merged into the existing file out instead, see output.MergeContent.
+func writeSchema(schema *conversion.GqlSchema, opts *cmdOptions) error {
+	out, split := opts.out, opts.split
+	if opts.merge && !isSingleFileOutput(out, split) {
+		return fmt.Errorf("merge requires --out to be a single .graphqls file")
+	}
+	if isSingleFileOutput(out, split) {
+		write := func() (bool, error) { return output.WriteFile(out, output.FileContent(schema)) }
+		if opts.merge {
+			write = func() (bool, error) { return output.MergeFile(out, schema) }
+		}
+		written, err := write()
+		if err != nil {
+			return err
+		}
+		if written {
+			fmt.Fprintln(os.Stderr, "written:", out)
+		}
+		return nil
+	}
+
+	files, err := output.SplitSchema(schema, split)
 	if err != nil {
 		return err
 	}
-	fmt.Println(prettyPrint)
+	report, err := output.WriteFiles(out, files)
+	for _, written := range report.Written {
+		fmt.Fprintln(os.Stderr, "written:", written)
+	}
+	for _, removed := range report.Removed {
+		fmt.Fprintln(os.Stderr, "removed:", removed)
+	}
+	return err
+}
+
+// printDiagnostics prints the diagnostics on stderr, one per line with the text format or as a JSON array with the json format.
+func printDiagnostics(diagnostics conversion.Diagnostics, format string) error {
+	if format == "json" {
+		if diagnostics == nil {
+			diagnostics = conversion.Diagnostics{}
+		}
+		encoder := json.NewEncoder(os.Stderr)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diagnostics)
+	}
+	for _, diagnostic := range diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+	}
 	return nil
 }
+
+// schemaFlags returns the flags selecting the structs to import and how they are converted into a GraphQL schema.
+func schemaFlags(opts *cmdOptions) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "src",
+			Usage:       "`SRC_PATH` is the path to the source file, or the package directory, containing the structs to import. A directory path ending with /... also includes the packages of its sub-directories. Required unless a configuration file is used, the configuration file is ignored when this is set",
+			Destination: &opts.fNameContStruct,
+			Aliases:     []string{"s"},
+		},
+		&cli.BoolFlag{
+			Name:        "use-json-tags",
+			Usage:       "Use JSON Tag as field name when available. If this is selected and a field has no Json tag, then the field name will be used.",
+			Destination: &opts.printOpts.UseJsonTags,
+			Aliases:     []string{"j"},
+		},
+		&cli.StringFlag{
+			Name:        "use-custom-tags",
+			Usage:       "Specify a custom tag to use as field name. Specifying this takes precedence over JSON tags. If specifed and a field does not have this tag, the field name will be used",
+			Destination: &opts.printOpts.UseCustomTags,
+			Aliases:     []string{"c"},
+		},
+		&cli.StringFlag{
+			Name:    "tags-value-ignored",
+			Usage:   "Specify a tag value that signal to ignore Field with tag having this value. When using json tags with use-json-tags option, if this not specified, it is automatically set to '-'",
+			Aliases: []string{"i"},
+			Action: func(context *cli.Context, s string) error {
+				// workaround as Destination: opts.printOpts.TagFieldToIgnore does not set the provided value
+				opts.printOpts.TagFieldToIgnore = &s
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:    "required-tags",
+			Usage:   "If there is a tag that make a field required, specified that tag using the format `key=value`. e.g. validate=required",
+			Aliases: []string{"r"},
+			Action: func(context *cli.Context, required string) error {
+				requireTags, err := config.ParseRequiredTag(required)
+				if err != nil {
+					return err
+				}
+				opts.printOpts.RequireTags = requireTags
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:        "int-policy",
+			Usage:       "Specify how integers that may not fit in a GraphQL Int (signed 32-bit) are converted: `POLICY` is 'strict' (BigInt scalar), 'lenient' (Int), 'string' (String) or the name of a custom scalar, or of a built-in scalar such as ID or Float, which is not declared. If not specified, only int64, uint64 and uintptr are converted to a BigInt scalar",
+			Destination: &opts.convertOpts.IntPolicy,
+		},
+		&cli.BoolFlag{
+			Name:        "unwrap-named-basic",
+			Usage:       "Convert named basic types (e.g. type Email string) into their base GraphQL type instead of a custom scalar named after the type",
+			Destination: &opts.convertOpts.UnwrapNamedBasic,
+		},
+		&cli.BoolFlag{
+			Name:        "detect-marshalers",
+			Usage:       "Convert types implementing gqlgen's graphql.Marshaler and graphql.Unmarshaler, or json.Marshaler, into a custom scalar named after the type, and types implementing encoding.TextMarshaler into a String. The fields converted this way are reported on stderr",
+			Destination: &opts.convertOpts.DetectMarshalers,
+		},
+		&cli.StringFlag{
+			Name:        "unsupported-types",
+			Usage:       "Specify how fields of a type GraphQL cannot represent (channels, funcs, unsafe.Pointer, complex numbers) are converted: `POLICY` is 'error' (abort), 'skip' (leave the field out) or 'scalar' (custom scalar). Skipped and scalar fields are reported on stderr. If not specified, channels and funcs abort while unsafe.Pointer and complex numbers are custom scalars",
+			Destination: &opts.convertOpts.UnsupportedPolicy,
+		},
+		&cli.StringFlag{
+			Name:        "empty-types",
+			Usage:       "Specify how the structs without any field, or without any field left once the ignored fields are left out, are converted: `POLICY` is 'skip' (leave the type and the fields referencing it out), 'placeholder' (add a '" + conversion.EmptyPlaceholderField + ": Boolean' field) or 'scalar' (custom scalar). If not specified, they are converted into an empty type, which is not valid GraphQL",
+			Destination: &opts.printOpts.EmptyPolicy,
+		},
+		&cli.BoolFlag{
+			Name:        "go-model-directives",
+			Usage:       "Bind the types to the Go structs for gqlgen: add @goModel to each type converted from a struct, @goField to its fields gqlgen would not bind to the Go field by name, and the definitions of these directives",
+			Destination: &opts.printOpts.GoModelDirectives,
+		},
+		&cli.StringFlag{
+			Name:        "connections",
+			Usage:       "Specify the fields listing objects replaced by a Relay connection, with the first, after, last and before arguments, and the XConnection, XEdge and PageInfo types: `POLICY` is 'tagged' (the fields tagged gql:\"connection\") or 'all' (every field listing objects)",
+			Value:       conversion.ConnectionsTagged,
+			Destination: &opts.printOpts.Connections,
+		},
+		&cli.BoolFlag{
+			Name:        "operations",
+			Usage:       "Extend the Query and Mutation types with the CRUD operations of the structs marked with a //gql:entity comment, e.g. article, articles, createArticle, updateArticle and deleteArticle, with the ArticleInput and ArticlePayload types they use",
+			Destination: &opts.printOpts.Operations,
+		},
+		&cli.StringFlag{
+			Name:        "relations",
+			Usage:       "Infer a relation from each field whose Go field is named after an object type followed by ID or Id, e.g. author: Author from AuthorID when there is an Author type, resolved from the foreign key: `POLICY` is 'add' (next to the foreign key field) or 'replace' (instead of the foreign key field). The relations inferred are reported on stderr",
+			Destination: &opts.printOpts.Relations,
+		},
+		&cli.BoolFlag{
+			Name:        "federation",
+			Usage:       "Write an Apollo Federation v2 subgraph schema: add @key(fields: \"...\") to the structs with fields tagged gql:\"key\" or a //gql:key FIELDS comment, @shareable to the structs with a //gql:shareable comment and to the fields tagged gql:\"shareable\", @external to the fields tagged gql:\"external\", and the extend schema @link header importing them. The keys are validated against the fields of the types",
+			Destination: &opts.printOpts.Federation,
+		},
+		&cli.StringSliceFlag{
+			Name:  "id-fields",
+			Usage: "Convert the Int, String and BigInt fields whose Go field is named `NAME`, compared ignoring case, into the GraphQL ID type, e.g. --id-fields ID also converts the Id fields. Can be repeated. The fields tagged gql:\"type=ID\" are always converted",
+			Action: func(context *cli.Context, names []string) error {
+				opts.printOpts.IDRules.Names = names
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:        "id-references",
+			Usage:       "Convert the Int, String and BigInt fields whose Go field is named after an object type followed by ID or Id, e.g. AuthorID when there is an Author type, into the GraphQL ID type",
+			Destination: &opts.printOpts.IDRules.References,
+		},
+		&cli.StringSliceFlag{
+			Name:  "directive",
+			Usage: "Define a custom directive, e.g. --directive 'directive @auth(requires: Role!) on OBJECT | FIELD_DEFINITION', whose `DEFINITION` is added to the schema when the fields tagged gqldirective:\"@auth(requires: ADMIN)\" or the structs with a //gql:directive @auth(requires: ADMIN) comment use it. Can be repeated. The directives used are validated against their definitions",
+			Action: func(context *cli.Context, definitions []string) error {
+				opts.printOpts.DirectiveDefinitions = definitions
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:        "order",
+			Usage:       "Specify the `ORDER` of the types and scalars: 'alpha' (by name), 'source' (types in declaration order, scalars in order of first use) or 'topo' (types after the types they depend on, scalars in order of first use)",
+			Value:       conversion.OrderAlpha,
+			Destination: &opts.printOpts.Order,
+		},
+		&cli.BoolFlag{
+			Name:        "sort-fields",
+			Usage:       "Sort the fields of each type by name instead of keeping the declaration order",
+			Destination: &opts.printOpts.SortFields,
+		},
+		&cli.BoolFlag{
+			Name:        "strict",
+			Usage:       "Fail when the schema violates the GraphQL specification, e.g. an empty type or a reference to an undefined type, instead of reporting the violations as warnings",
+			Destination: &opts.strict,
+		},
+		&cli.StringFlag{
+			Name:        "diagnostics-format",
+			Usage:       "Specify the `FORMAT` of the diagnostics (errors, warnings and information about the conversion) printed on stderr: 'text' or 'json'",
+			Value:       "text",
+			Destination: &opts.diagnosticsFormat,
+			Action: func(context *cli.Context, format string) error {
+				if format != "text" && format != "json" {
+					return fmt.Errorf("invalid format for diagnostics-format, expected text or json")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// generateFlags returns the flags of the generate command.
+func generateFlags(opts *cmdOptions) []cli.Flag {
+	flags := append(configFlags(opts), schemaFlags(opts)...)
+	return append(flags, outputFlags(opts)...)
+}
+
+// configFlags returns the flags selecting the configuration file and its targets.
+func configFlags(opts *cmdOptions) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "config",
+			Usage:       "Read the targets from the configuration file `CONFIG_PATH` when --src is not set. If not specified, the " + config.FileName + " file of the working directory or of its closest parent directory is used",
+			Destination: &opts.configPath,
+		},
+		&cli.StringFlag{
+			Name:        "target",
+			Usage:       "Only use the target `NAME` of the configuration file instead of all of them",
+			Destination: &opts.target,
+		},
+	}
+}
+
+// outputFlags returns the flags selecting where the schema is written.
+func outputFlags(opts *cmdOptions) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "out",
+			Usage:       "Write the schema into `OUT_PATH` instead of stdout: a .graphqls file, or a directory. Only the files whose content changed are rewritten, and the files of the directory generated earlier that are not generated anymore are removed",
+			Aliases:     []string{"o"},
+			Destination: &opts.out,
+		},
+		&cli.StringFlag{
+			Name:        "split",
+			Usage:       "Specify how the schema written with --out is split into files: `MODE` is 'none' (a single file), 'scalars' (schema.graphqls and scalars.graphqls), 'package' (one file per Go package and scalars.graphqls) or 'type' (one file per type and scalars.graphqls)",
+			Value:       output.SplitNone,
+			Destination: &opts.split,
+		},
+		&cli.BoolFlag{
+			Name:        "merge",
+			Usage:       "Merge the schema into the existing .graphqls file set with --out, keeping its hand-written definitions: only the region between the '" + output.BeginMarker + "' and '" + output.EndMarker + "' lines, or else only the types and scalars generated, identified by name and kind, are updated",
+			Destination: &opts.merge,
+		},
+		&cli.StringFlag{
+			Name:        "gqlgen-config",
+			Usage:       "Bind the types and custom scalars to their Go type in the models section of the gqlgen configuration file `GQLGEN_PATH`, e.g. " + gqlgen.ConfigFileName + ", keeping its comments and other keys. The fields that cannot be bound to a Go struct field get resolver: true",
+			Destination: &opts.gqlgenConfig,
+		},
+		&cli.StringFlag{
+			Name:        "scalars-go-out",
+			Usage:       "Generate a MarshalXxx and UnmarshalXxx function pair for each custom scalar into the Go file `GO_PATH`, in gqlgen's external marshalers style. The functions are implemented for booleans, numbers, strings, empty interfaces and json.Marshaler types, and left with a TODO body otherwise. When the file exists, only the missing functions are added. With --gqlgen-config, the scalars are bound to these functions",
+			Destination: &opts.scalarsGoOut,
+		},
+		&cli.StringFlag{
+			Name:        "scalars-go-package",
+			Usage:       "Specify the `PACKAGE` name of the Go file written with --scalars-go-out when it does not exist yet. If not specified, the package is named after the directory of the file",
+			Destination: &opts.scalarsGoPackage,
+		},
+		&cli.StringSliceFlag{
+			Name:  "gqlgen-scalar",
+			Usage: "Bind a custom scalar to the Go type implementing it in the gqlgen models, using the format `SCALAR=TYPE`, e.g. BigInt=github.com/99designs/gqlgen/graphql.Int64. Can be repeated. If not specified, a scalar is bound to the Go named type converted into it, and BigInt to " + gqlgen.DefaultScalarModels["BigInt"],
+			Action: func(context *cli.Context, scalarModels []string) error {
+				opts.gqlgenScalars = make(map[string]string, len(scalarModels))
+				for _, scalarModel := range scalarModels {
+					scalar, goType, err := config.ParseScalarModel(scalarModel)
+					if err != nil {
+						return err
+					}
+					opts.gqlgenScalars[scalar] = goType
+				}
+				return nil
+			},
+		},
+	}
+}
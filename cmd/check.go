@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/VintageOps/structogqlgen/pkg/output"
+	"github.com/urfave/cli/v2"
+)
+
+// checkCommand returns the check command, which checks a schema written earlier is up to date with the Go structs.
+func checkCommand() *cli.Command {
+	var opts cmdOptions
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Checks that the schema written with --out is up to date with the Go structs",
+		Description: "Regenerates the schema in memory with the same options as when it was written, and compares it with the schema files.\n" +
+			"It exits with a non-zero status and prints a unified diff when the schema files are out of date, e.g. to detect drift in CI.\n" +
+			"Without --src, it checks the out of every target of the configuration file, or of the one selected with --target.",
+		Flags: append(append(configFlags(&opts), schemaFlags(&opts)...),
+			&cli.StringFlag{
+				Name:        "schema",
+				Usage:       "`SCHEMA_PATH` is the path to the schema to check, as written with --out: a .graphqls file, or a directory. Required with --src",
+				Destination: &opts.out,
+			},
+			&cli.StringFlag{
+				Name:        "split",
+				Usage:       "Specify how the schema was split into files: `MODE` is 'none', 'scalars', 'package' or 'type'",
+				Value:       output.SplitNone,
+				Destination: &opts.split,
+			},
+			&cli.BoolFlag{
+				Name:        "merge",
+				Usage:       "Check the schema merged into the hand-written .graphqls file set with --schema, as written with --merge",
+				Destination: &opts.merge,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			return check(&opts)
+		},
+	}
+}
+
+// check checks the schema of each target, see resolveTargets.
+// It returns an error if any schema file is out of date.
+func check(opts *cmdOptions) error {
+	targets, err := resolveTargets(opts)
+	if err != nil {
+		return err
+	}
+	outdated := 0
+	for _, target := range targets {
+		if target.opts.out == "" {
+			if target.name == "" {
+				return fmt.Errorf("required flag \"schema\" not set")
+			}
+			return fmt.Errorf("target %s has no out to check", target.name)
+		}
+		targetOutdated, err := checkSchema(&target.opts)
+		if err != nil {
+			return target.wrapErr(err)
+		}
+		outdated += targetOutdated
+	}
+	if outdated != 0 {
+		return fmt.Errorf("%d schema file(s) out of date with the Go structs", outdated)
+	}
+	return nil
+}
+
+// checkSchema regenerates the schema and prints the unified diff of each schema file that is out of date.
+// It returns the number of files out of date, missing, or generated earlier but not generated anymore.
+func checkSchema(opts *cmdOptions) (int, error) {
+	schema, err := buildSchema(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	var files []output.File
+	dir := opts.out
+	if opts.merge {
+		if !isSingleFileOutput(opts.out, opts.split) {
+			return 0, fmt.Errorf("merge requires --schema to be a single .graphqls file")
+		}
+		existing, err := os.ReadFile(opts.out)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("failed to read the schema file, error was: %v", err)
+		}
+		content, err := output.MergeContent(string(existing), schema)
+		if err != nil {
+			return 0, err
+		}
+		dir = filepath.Dir(opts.out)
+		files = []output.File{{Name: filepath.Base(opts.out), Content: content}}
+	} else if isSingleFileOutput(opts.out, opts.split) {
+		dir = filepath.Dir(opts.out)
+		files = []output.File{{Name: filepath.Base(opts.out), Content: output.FileContent(schema)}}
+	} else {
+		files, err = output.SplitSchema(schema, opts.split)
+		if err != nil {
+			return 0, err
+		}
+		staleFiles, err := output.StaleFiles(dir, files)
+		if err != nil {
+			return 0, err
+		}
+		for _, staleFile := range staleFiles {
+			// A stale file is expected to be removed
+			files = append(files, output.File{Name: filepath.Base(staleFile)})
+		}
+	}
+
+	outdated := 0
+	for _, file := range files {
+		filePath := filepath.Join(dir, file.Name)
+		existing, err := os.ReadFile(filePath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("failed to read the schema file, error was: %v", err)
+		}
+		diff := output.UnifiedDiff(filePath, filePath+" (generated)", string(existing), file.Content)
+		if diff != "" {
+			outdated++
+			fmt.Print(diff)
+		}
+	}
+	return outdated, nil
+}
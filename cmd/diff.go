@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+	"github.com/VintageOps/structogqlgen/pkg/output"
+	"github.com/VintageOps/structogqlgen/pkg/revision"
+	"github.com/urfave/cli/v2"
+)
+
+// diffOptions represents the options of the diff command.
+type diffOptions struct {
+	previous     string
+	saveSnapshot string
+	revRange     string
+}
+
+// diffCommand returns the diff command, which classifies the changes of the schema since a previous version.
+func diffCommand() *cli.Command {
+	var opts cmdOptions
+	var diffOpts diffOptions
+	return &cli.Command{
+		Name:  "diff",
+		Usage: "Reports the changes of the schema since a previous version, and whether they break the clients",
+		Description: "Builds the schema of the structs and compares it with a previous schema, printing each change classified as BREAKING or SAFE.\n" +
+			"It exits with a non-zero status if any change is breaking: a type, scalar or field removed, a field type changed, or a non-null input field added.\n" +
+			"Without --src, it compares every target of the configuration file, or the one selected with --target, with its out when --previous is not set.\n" +
+			"With --rev, it compares the schemas built from the Go source at two git revisions instead, read from the local repository.",
+		Flags: append(append(configFlags(&opts), schemaFlags(&opts)...),
+			&cli.StringFlag{
+				Name:        "previous",
+				Usage:       "`PREVIOUS_PATH` is the path to the previous schema: a .graphqls file, a directory of .graphqls files, or a snapshot written with --save-snapshot. Required with --src",
+				Destination: &diffOpts.previous,
+			},
+			&cli.StringFlag{
+				Name:        "save-snapshot",
+				Usage:       "Write the schema built into the JSON snapshot file `SNAPSHOT_PATH`, to compare the next versions with it",
+				Destination: &diffOpts.saveSnapshot,
+			},
+			&cli.StringFlag{
+				Name:        "rev",
+				Usage:       "Compare the schemas built from the Go source at the git revisions `RANGE`: from..to (e.g. main..HEAD), or a single revision compared with the work tree",
+				Destination: &diffOpts.revRange,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			return diff(&opts, &diffOpts)
+		},
+	}
+}
+
+// diff compares the schema of each target, see resolveTargets, with its previous version.
+// It returns an error if any change is breaking.
+func diff(opts *cmdOptions, diffOpts *diffOptions) error {
+	targets, err := resolveTargets(opts)
+	if err != nil {
+		return err
+	}
+	if diffOpts.saveSnapshot != "" && len(targets) > 1 {
+		return fmt.Errorf("save-snapshot requires a single target, select one with --target")
+	}
+	if diffOpts.revRange != "" && diffOpts.previous != "" {
+		return fmt.Errorf("rev and previous cannot be used together")
+	}
+
+	var fromTree, toTree *revision.Tree
+	if diffOpts.revRange != "" {
+		from, to, err := revision.ParseRange(diffOpts.revRange)
+		if err != nil {
+			return err
+		}
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		// Only the modules of the sources are extracted
+		var srcPaths []string
+		for _, target := range targets {
+			srcPaths = append(srcPaths, target.opts.fNameContStruct)
+		}
+		if fromTree, err = revision.Extract(wd, from, srcPaths...); err != nil {
+			return err
+		}
+		defer fromTree.Remove()
+		if to != "" {
+			if toTree, err = revision.Extract(wd, to, srcPaths...); err != nil {
+				return err
+			}
+			defer toTree.Remove()
+		}
+	}
+
+	breaking := 0
+	for _, target := range targets {
+		var previous, current *conversion.GqlSchema
+		if fromTree != nil {
+			previous, err = buildSchemaAtRevision(&target.opts, fromTree)
+		} else {
+			previous, err = readPreviousSchema(&target, diffOpts.previous)
+		}
+		if err != nil {
+			return target.wrapErr(err)
+		}
+		if toTree != nil {
+			current, err = buildSchemaAtRevision(&target.opts, toTree)
+		} else {
+			current, err = buildSchema(&target.opts)
+		}
+		if err != nil {
+			return target.wrapErr(err)
+		}
+		if target.name != "" {
+			fmt.Printf("target %s:\n", target.name)
+		}
+		breaking += printSchemaChanges(conversion.CompareSchemas(previous, current))
+
+		if diffOpts.saveSnapshot != "" {
+			if err := output.WriteSnapshot(diffOpts.saveSnapshot, current); err != nil {
+				return err
+			}
+		}
+	}
+	if breaking != 0 {
+		return fmt.Errorf("%d breaking change(s)", breaking)
+	}
+	return nil
+}
+
+// readPreviousSchema reads the previous schema of the target at previousPath, or else at the out of the target.
+func readPreviousSchema(target *cmdTarget, previousPath string) (*conversion.GqlSchema, error) {
+	if previousPath == "" {
+		previousPath = target.opts.out
+	}
+	if previousPath == "" {
+		if target.name == "" {
+			return nil, fmt.Errorf("required flag \"previous\" not set")
+		}
+		return nil, fmt.Errorf("no out to compare with, set --previous")
+	}
+	return output.ReadSchema(previousPath)
+}
+
+// buildSchemaAtRevision builds the schema of the structs found in the source path as it is in the revision tree.
+func buildSchemaAtRevision(opts *cmdOptions, tree *revision.Tree) (*conversion.GqlSchema, error) {
+	src, err := tree.Path(opts.fNameContStruct)
+	if err != nil {
+		return nil, err
+	}
+	revOpts := *opts
+	revOpts.fNameContStruct = src
+	schema, err := buildSchema(&revOpts)
+	if err != nil {
+		return nil, fmt.Errorf("revision %s: %v", tree.Rev, err)
+	}
+	return schema, nil
+}
+
+// printSchemaChanges prints the changes, one per line, and returns the number of breaking changes.
+func printSchemaChanges(changes conversion.SchemaChanges) int {
+	breaking := 0
+	for _, change := range changes {
+		fmt.Println(change)
+		if change.Breaking {
+			breaking++
+		}
+	}
+	return breaking
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/VintageOps/structogqlgen/pkg/config"
+	"github.com/urfave/cli/v2"
+)
+
+// initCommand returns the init command, which writes a starter configuration file from the flags.
+func initCommand() *cli.Command {
+	var opts cmdOptions
+	var force bool
+	return &cli.Command{
+		Name:  "init",
+		Usage: "Writes a starter configuration file using the provided flags",
+		Description: "Writes a configuration file declaring a single target named " + initTargetName + ", converted with the provided flags.\n" +
+			"If --src is not set, the target uses the packages of the working directory and its sub-directories.",
+		Flags: append(append(schemaFlags(&opts), outputFlags(&opts)...),
+			&cli.StringFlag{
+				Name:        "config",
+				Usage:       "Write the configuration file into `CONFIG_PATH`",
+				Value:       config.FileName,
+				Destination: &opts.configPath,
+			},
+			&cli.BoolFlag{
+				Name:        "force",
+				Usage:       "Overwrite the configuration file if it already exists",
+				Destination: &force,
+			},
+		),
+		Action: func(c *cli.Context) error {
+			return initConfig(&opts, force)
+		},
+	}
+}
+
+// initTargetName is the name of the target of the starter configuration file.
+const initTargetName = "default"
+
+// initConfig writes the starter configuration file at the --config path, refusing to overwrite an existing file unless force is set.
+func initConfig(opts *cmdOptions, force bool) error {
+	if _, err := os.Stat(opts.configPath); err == nil && !force {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", opts.configPath)
+	}
+
+	src := opts.fNameContStruct
+	if src == "" {
+		src = "./..."
+	}
+	target := config.NewTarget(initTargetName, src, opts.convertOpts, opts.printOpts, opts.out, opts.split, opts.merge)
+	target.Strict = opts.strict
+	target.GqlgenConfig = opts.gqlgenConfig
+	target.GqlgenScalars = opts.gqlgenScalars
+	target.ScalarsGoOut = opts.scalarsGoOut
+	target.ScalarsGoPackage = opts.scalarsGoPackage
+	cfg := &config.Config{Targets: []config.Target{target}}
+	if err := config.Write(opts.configPath, cfg); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "written:", opts.configPath)
+	return nil
+}
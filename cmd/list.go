@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// listCommand returns the list command, which lists the targets and the GraphQL types they generate.
+func listCommand() *cli.Command {
+	var opts cmdOptions
+	return &cli.Command{
+		Name:  "list",
+		Usage: "Lists the targets and the GraphQL types they generate",
+		Description: "Prints each target with its source and output paths, followed by the GraphQL types generated from its structs and their position in the Go source.\n" +
+			"Without --src, it lists every target of the configuration file, or the one selected with --target.",
+		Flags: append(configFlags(&opts), schemaFlags(&opts)...),
+		Action: func(c *cli.Context) error {
+			return list(&opts)
+		},
+	}
+}
+
+// list prints each target, see resolveTargets, with the types of its schema.
+func list(opts *cmdOptions) error {
+	targets, err := resolveTargets(opts)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		schema, err := buildSchema(&target.opts)
+		if err != nil {
+			return target.wrapErr(err)
+		}
+
+		name := target.name
+		if name == "" {
+			name = "(flags)"
+		}
+		out := target.opts.out
+		if out == "" {
+			out = "stdout"
+		}
+		fmt.Printf("%s: %s -> %s\n", name, target.opts.fNameContStruct, out)
+		for _, schemaType := range schema.Types {
+			if schemaType.Position.IsValid() {
+				fmt.Printf("  type %s\t%s\n", schemaType.Name, schemaType.Position)
+			} else {
+				fmt.Printf("  type %s\n", schemaType.Name)
+			}
+		}
+		for _, scalar := range schema.Scalars {
+			fmt.Printf("  scalar %s\n", scalar.Name)
+		}
+	}
+	return nil
+}
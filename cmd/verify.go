@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// verifyCommand returns the verify command, which verifies gqlgen binds the fields of the schema to the Go structs.
+func verifyCommand() *cli.Command {
+	var opts cmdOptions
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "Verifies that gqlgen binds every field of the schema to its Go struct field",
+		Description: "Generates the schema in memory and simulates how gqlgen binds its fields to the fields and methods of the Go structs, by name ignoring the case and the underscores.\n" +
+			"It reports every field gqlgen would not bind to its Go struct field, e.g. a field renamed by its JSON tag, which needs a @goField directive or a resolver,\n" +
+			"and exits with a non-zero status if there is any. The @goField directives added with --go-model-directives are taken into account.\n" +
+			"Without --src, it verifies every target of the configuration file, or the one selected with --target.",
+		Flags: append(configFlags(&opts), schemaFlags(&opts)...),
+		Action: func(c *cli.Context) error {
+			return verify(&opts)
+		},
+	}
+}
+
+// verify builds the schema of each target, see resolveTargets, verifying gqlgen binds its fields to the Go structs,
+// see conversion.VerifyGqlgenBinding. The fields it would not bind are reported as errors.
+func verify(opts *cmdOptions) error {
+	targets, err := resolveTargets(opts)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		target.opts.verifyBinding = true
+		if _, err := buildSchema(&target.opts); err != nil {
+			return target.wrapErr(err)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ go 1.22.1
 require (
 	github.com/fatih/structtag v1.2.0
 	github.com/urfave/cli/v2 v2.27.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,330 @@
+// Package config provides functionalities to find, load and write the project configuration file of structogqlgen,
+// which declares the schema targets to generate: where their structs are, how they are converted and where they are written.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+	"github.com/VintageOps/structogqlgen/pkg/output"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project configuration file.
+const FileName = ".structogqlgen.yml"
+
+// Config represents a project configuration file.
+type Config struct {
+	Targets []Target `yaml:"targets"`
+	Path    string   `yaml:"-"` // Path is the path of the file the configuration was loaded from, if any
+}
+
+// Target represents a schema to generate. It contains the following fields:
+// - Name: a string naming the target, unique within the configuration
+// - Src: a string, the path to the source file, or the package directory, containing the structs. A directory path
+// ending with /... also includes the packages of its sub-directories.
+// - Out: a string, the path to the .graphqls file, or the directory, the schema is written into. When empty, the schema is
+// printed on stdout.
+// - Split: a string selecting how the schema is split into files, see package github.com/VintageOps/structogqlgen/pkg/output
+// - Merge: a bool indicating whether the schema is merged into the hand-written file Out rather than overwriting it
+// - GqlgenConfig: a string, the path to the gqlgen configuration file whose models section is updated, if any
+// - GqlgenScalars: a map from custom scalars to the Go type implementing them in the models section, see package
+// github.com/VintageOps/structogqlgen/pkg/gqlgen
+// - ScalarsGoOut and ScalarsGoPackage: the path to the Go file the marshalers of the custom scalars are generated into,
+// if any, and the name of its package
+// - Order and SortFields: the ordering of the schema, see conversion.PrettyPrintOptions
+// - EmptyTypes: a string selecting how the structs without any field are converted, see conversion.PrettyPrintOptions
+// - GoModelDirectives: a bool indicating whether the gqlgen directives binding the types to the Go structs are added
+// - Connections: a string selecting the list fields replaced by a Relay connection, see conversion.PrettyPrintOptions
+// - Operations: a bool indicating whether the CRUD operations of the entities are generated, see conversion.PrettyPrintOptions
+// - Relations: a string selecting how the relations inferred from the foreign key fields are added, see conversion.PrettyPrintOptions
+// - Federation: a bool indicating whether an Apollo Federation subgraph schema is written, see conversion.PrettyPrintOptions
+// - IDs: an IDRules struct selecting the fields converted into the GraphQL ID type
+// - Directives: a slice of the definitions of the custom directives of the tags and doc comments, see conversion.PrettyPrintOptions
+// - Tags: a TagRules struct selecting the tags used to name, ignore and require fields
+// - Conversion: a ConversionRules struct selecting how Go types are converted
+// - Mappings: a map from Go named types to the GraphQL type they are converted into, see conversion.ConvertOptions
+// - Strict: a bool indicating whether the violations of the GraphQL specification found in the schema fail the run
+//
+// The relative paths are relative to the directory of the configuration file.
+type Target struct {
+	Name              string            `yaml:"name"`
+	Src               string            `yaml:"src"`
+	Out               string            `yaml:"out,omitempty"`
+	Split             string            `yaml:"split,omitempty"`
+	Merge             bool              `yaml:"merge,omitempty"`
+	GqlgenConfig      string            `yaml:"gqlgen-config,omitempty"`
+	GqlgenScalars     map[string]string `yaml:"gqlgen-scalars,omitempty"`
+	ScalarsGoOut      string            `yaml:"scalars-go-out,omitempty"`
+	ScalarsGoPackage  string            `yaml:"scalars-go-package,omitempty"`
+	Order             string            `yaml:"order,omitempty"`
+	SortFields        bool              `yaml:"sort-fields,omitempty"`
+	EmptyTypes        string            `yaml:"empty-types,omitempty"`
+	GoModelDirectives bool              `yaml:"go-model-directives,omitempty"`
+	Connections       string            `yaml:"connections,omitempty"`
+	Operations        bool              `yaml:"operations,omitempty"`
+	Relations         string            `yaml:"relations,omitempty"`
+	Federation        bool              `yaml:"federation,omitempty"`
+	IDs               IDRules           `yaml:"ids,omitempty"`
+	Directives        []string          `yaml:"directives,omitempty"`
+	Tags              TagRules          `yaml:"tags,omitempty"`
+	Conversion        ConversionRules   `yaml:"conversion,omitempty"`
+	Mappings          map[string]string `yaml:"mappings,omitempty"`
+	Strict            bool              `yaml:"strict,omitempty"`
+}
+
+// TagRules represents the tags used to name, ignore and require fields, see conversion.PrettyPrintOptions.
+// Required uses the format key=value, e.g. validate=required.
+type TagRules struct {
+	UseJsonTags   bool    `yaml:"use-json-tags,omitempty"`
+	UseCustomTags string  `yaml:"use-custom-tags,omitempty"`
+	ValueIgnored  *string `yaml:"value-ignored,omitempty"`
+	Required      string  `yaml:"required,omitempty"`
+}
+
+// IDRules represents the fields converted into the GraphQL ID type, see conversion.IDRules.
+type IDRules struct {
+	Fields     []string `yaml:"fields,omitempty"`
+	References bool     `yaml:"references,omitempty"`
+}
+
+// ConversionRules represents how Go types are converted, see conversion.ConvertOptions.
+type ConversionRules struct {
+	IntPolicy         string `yaml:"int-policy,omitempty"`
+	UnwrapNamedBasic  bool   `yaml:"unwrap-named-basic,omitempty"`
+	DetectMarshalers  bool   `yaml:"detect-marshalers,omitempty"`
+	UnsupportedPolicy string `yaml:"unsupported-types,omitempty"`
+}
+
+// Find returns the path of the configuration file found in the directory dir or in the closest of its parent directories.
+// It returns an empty path if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		filePath := filepath.Join(dir, FileName)
+		info, err := os.Stat(filePath)
+		if err == nil && !info.IsDir() {
+			return filePath, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to look for the configuration file, error was: %v", err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads the configuration file at filePath and validates it.
+// The relative paths of the targets are resolved against the directory of the configuration file.
+func Load(filePath string) (*Config, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the configuration file, error was: %v", err)
+	}
+
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse the configuration file %s, error was: %v", filePath, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %v", filePath, err)
+	}
+
+	cfg.Path = filePath
+	dir := filepath.Dir(filePath)
+	for idx := range cfg.Targets {
+		cfg.Targets[idx].Src = resolvePath(dir, cfg.Targets[idx].Src)
+		cfg.Targets[idx].Out = resolvePath(dir, cfg.Targets[idx].Out)
+		cfg.Targets[idx].GqlgenConfig = resolvePath(dir, cfg.Targets[idx].GqlgenConfig)
+		cfg.Targets[idx].ScalarsGoOut = resolvePath(dir, cfg.Targets[idx].ScalarsGoOut)
+	}
+	return &cfg, nil
+}
+
+// resolvePath returns the path p relative to the directory dir, unless it is empty or absolute.
+func resolvePath(dir string, p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(dir, p)
+}
+
+// validate returns an error if the configuration is not valid, e.g. if a target uses an unknown policy or split mode.
+func (cfg *Config) validate() error {
+	if len(cfg.Targets) == 0 {
+		return fmt.Errorf("no target declared")
+	}
+	names := make(map[string]bool, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		if target.Name == "" {
+			return fmt.Errorf("a target has no name")
+		}
+		if names[target.Name] {
+			return fmt.Errorf("target %s is declared more than once", target.Name)
+		}
+		names[target.Name] = true
+		if target.Src == "" {
+			return fmt.Errorf("target %s has no src", target.Name)
+		}
+		printOpts, err := target.PrettyPrintOptions()
+		if err == nil {
+			err = printOpts.Validate()
+		}
+		if err != nil {
+			return fmt.Errorf("target %s: %v", target.Name, err)
+		}
+		convertOpts := target.ConvertOptions()
+		if err := convertOpts.Validate(); err != nil {
+			return fmt.Errorf("target %s: %v", target.Name, err)
+		}
+		if err := output.ValidateSplit(target.Split); err != nil {
+			return fmt.Errorf("target %s: %v", target.Name, err)
+		}
+		for scalar, goType := range target.GqlgenScalars {
+			if goType == "" {
+				return fmt.Errorf("target %s: no Go type for the gqlgen scalar %s", target.Name, scalar)
+			}
+		}
+	}
+	return nil
+}
+
+// SelectTargets returns the target named name, or all the targets if name is empty.
+func (cfg *Config) SelectTargets(name string) ([]Target, error) {
+	if name == "" {
+		return cfg.Targets, nil
+	}
+	for _, target := range cfg.Targets {
+		if target.Name == name {
+			return []Target{target}, nil
+		}
+	}
+	return nil, fmt.Errorf("no target named %s in the configuration file %s", name, cfg.Path)
+}
+
+// ConvertOptions returns the conversion.ConvertOptions of the target.
+func (target *Target) ConvertOptions() conversion.ConvertOptions {
+	return conversion.ConvertOptions{
+		IntPolicy:         target.Conversion.IntPolicy,
+		UnwrapNamedBasic:  target.Conversion.UnwrapNamedBasic,
+		DetectMarshalers:  target.Conversion.DetectMarshalers,
+		UnsupportedPolicy: target.Conversion.UnsupportedPolicy,
+		TypeMappings:      target.Mappings,
+	}
+}
+
+// PrettyPrintOptions returns the conversion.PrettyPrintOptions of the target.
+// It returns an error if the required tag does not use the format key=value.
+func (target *Target) PrettyPrintOptions() (conversion.PrettyPrintOptions, error) {
+	printOpts := conversion.PrettyPrintOptions{
+		UseJsonTags:          target.Tags.UseJsonTags,
+		UseCustomTags:        target.Tags.UseCustomTags,
+		TagFieldToIgnore:     target.Tags.ValueIgnored,
+		Order:                target.Order,
+		SortFields:           target.SortFields,
+		EmptyPolicy:          target.EmptyTypes,
+		GoModelDirectives:    target.GoModelDirectives,
+		Connections:          target.Connections,
+		Operations:           target.Operations,
+		Relations:            target.Relations,
+		Federation:           target.Federation,
+		IDRules:              conversion.IDRules{Names: target.IDs.Fields, References: target.IDs.References},
+		DirectiveDefinitions: target.Directives,
+	}
+	if target.Tags.Required != "" {
+		requireTags, err := ParseRequiredTag(target.Tags.Required)
+		if err != nil {
+			return printOpts, err
+		}
+		printOpts.RequireTags = requireTags
+	}
+	return printOpts, nil
+}
+
+// ParseRequiredTag parses a tag that makes a field required, using the format key=value, e.g. validate=required.
+func ParseRequiredTag(required string) (conversion.SpecTagRequire, error) {
+	parts := strings.SplitN(required, "=", 2)
+	if len(parts) != 2 {
+		return conversion.SpecTagRequire{}, fmt.Errorf("invalid format for required tags, expected key=value")
+	}
+	return conversion.SpecTagRequire{Key: parts[0], Val: parts[1]}, nil
+}
+
+// ParseScalarModel parses the Go type implementing a custom scalar in the gqlgen models, using the format scalar=type,
+// e.g. BigInt=github.com/99designs/gqlgen/graphql.Int64.
+func ParseScalarModel(scalarModel string) (string, string, error) {
+	scalar, goType, ok := strings.Cut(scalarModel, "=")
+	if !ok || scalar == "" || goType == "" {
+		return "", "", fmt.Errorf("invalid format for the scalar model %q, expected scalar=type", scalarModel)
+	}
+	return scalar, goType, nil
+}
+
+// NewTarget returns a target named name using the provided options, e.g. to write a starter configuration.
+func NewTarget(name string, src string, convertOpts conversion.ConvertOptions, printOpts conversion.PrettyPrintOptions, out string, split string, merge bool) Target {
+	target := Target{
+		Name:              name,
+		Src:               src,
+		Out:               out,
+		Split:             split,
+		Merge:             merge,
+		Order:             printOpts.Order,
+		SortFields:        printOpts.SortFields,
+		EmptyTypes:        printOpts.EmptyPolicy,
+		GoModelDirectives: printOpts.GoModelDirectives,
+		Connections:       printOpts.Connections,
+		Operations:        printOpts.Operations,
+		Relations:         printOpts.Relations,
+		Federation:        printOpts.Federation,
+		IDs:               IDRules{Fields: printOpts.IDRules.Names, References: printOpts.IDRules.References},
+		Directives:        printOpts.DirectiveDefinitions,
+		Tags: TagRules{
+			UseJsonTags:   printOpts.UseJsonTags,
+			UseCustomTags: printOpts.UseCustomTags,
+			ValueIgnored:  printOpts.TagFieldToIgnore,
+		},
+		Conversion: ConversionRules{
+			IntPolicy:         convertOpts.IntPolicy,
+			UnwrapNamedBasic:  convertOpts.UnwrapNamedBasic,
+			DetectMarshalers:  convertOpts.DetectMarshalers,
+			UnsupportedPolicy: convertOpts.UnsupportedPolicy,
+		},
+		Mappings: convertOpts.TypeMappings,
+	}
+	if printOpts.RequireTags.Key != "" {
+		target.Tags.Required = printOpts.RequireTags.Key + "=" + printOpts.RequireTags.Val
+	}
+	return target
+}
+
+// Write writes the configuration into the file at filePath, in YAML.
+func Write(filePath string, cfg *Config) error {
+	var content bytes.Buffer
+	content.WriteString("# Configuration of structogqlgen, see https://github.com/VintageOps/structogqlgen\n")
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to encode the configuration, error was: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode the configuration, error was: %v", err)
+	}
+	if err := os.WriteFile(filePath, content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write the configuration file, error was: %v", err)
+	}
+	return nil
+}
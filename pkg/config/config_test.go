@@ -0,0 +1,159 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// writeConfig writes a configuration file with the provided content into the directory dir and returns its path.
+func writeConfig(t *testing.T, dir string, content string) string {
+	t.Helper()
+	filePath := filepath.Join(dir, FileName)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
+
+// TestFind is a unit test for the Find function.
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	subDir := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Find(subDir)
+	if err != nil || got != "" {
+		t.Errorf("Find() without configuration = %q, %v, want no path", got, err)
+	}
+
+	want := writeConfig(t, dir, "targets: []\n")
+	got, err = Find(subDir)
+	if err != nil || got != want {
+		t.Errorf("Find() = %q, %v, want %q", got, err, want)
+	}
+}
+
+// TestLoad is a unit test for the Load function.
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "Valid", content: "targets:\n  - name: models\n    src: ./models/...\n    out: graph/schema.graphqls\n    gqlgen-config: gqlgen.yml\n    scalars-go-out: graph/scalars.go\n"},
+		{name: "NoTarget", content: "targets: []\n", wantErr: true},
+		{name: "NoName", content: "targets:\n  - src: ./models\n", wantErr: true},
+		{name: "NoSrc", content: "targets:\n  - name: models\n", wantErr: true},
+		{name: "DuplicateName", content: "targets:\n  - name: models\n    src: a\n  - name: models\n    src: b\n", wantErr: true},
+		{name: "UnknownKey", content: "targets:\n  - name: models\n    src: a\n    output: b\n", wantErr: true},
+		{name: "EmptyGqlgenScalar", content: "targets:\n  - name: models\n    src: a\n    gqlgen-scalars:\n      BigInt: \"\"\n", wantErr: true},
+		{name: "InvalidRequiredTag", content: "targets:\n  - name: models\n    src: a\n    tags:\n      required: validate\n", wantErr: true},
+		{name: "InvalidSplit", content: "targets:\n  - name: models\n    src: a\n    split: file\n", wantErr: true},
+		{name: "InvalidOrder", content: "targets:\n  - name: models\n    src: a\n    order: random\n", wantErr: true},
+		{name: "InvalidEmptyTypes", content: "targets:\n  - name: models\n    src: a\n    empty-types: drop\n", wantErr: true},
+		{name: "InvalidConnections", content: "targets:\n  - name: models\n    src: a\n    connections: some\n", wantErr: true},
+		{name: "InvalidRelations", content: "targets:\n  - name: models\n    src: a\n    relations: infer\n", wantErr: true},
+		{name: "InvalidDirective", content: "targets:\n  - name: models\n    src: a\n    directives: [\"directive @auth on NOWHERE\"]\n", wantErr: true},
+		{name: "InvalidIntPolicy", content: "targets:\n  - name: models\n    src: a\n    conversion:\n      int-policy: big-int\n", wantErr: true},
+		{name: "InvalidUnsupportedTypes", content: "targets:\n  - name: models\n    src: a\n    conversion:\n      unsupported-types: ignore\n", wantErr: true},
+		{name: "InvalidMapping", content: "targets:\n  - name: models\n    src: a\n    mappings:\n      time.Time: date-time\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filePath := writeConfig(t, dir, tt.content)
+			cfg, err := Load(filePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				// The error names the configuration file
+				if !strings.Contains(err.Error(), filePath) {
+					t.Errorf("Load() error = %v, want it to name %s", err, filePath)
+				}
+				return
+			}
+			// Relative paths are resolved against the directory of the configuration file
+			if want := filepath.Join(dir, "models", "..."); cfg.Targets[0].Src != want {
+				t.Errorf("Load() src = %s, want %s", cfg.Targets[0].Src, want)
+			}
+			if want := filepath.Join(dir, "graph", "schema.graphqls"); cfg.Targets[0].Out != want {
+				t.Errorf("Load() out = %s, want %s", cfg.Targets[0].Out, want)
+			}
+			if want := filepath.Join(dir, "gqlgen.yml"); cfg.Targets[0].GqlgenConfig != want {
+				t.Errorf("Load() gqlgen-config = %s, want %s", cfg.Targets[0].GqlgenConfig, want)
+			}
+			if want := filepath.Join(dir, "graph", "scalars.go"); cfg.Targets[0].ScalarsGoOut != want {
+				t.Errorf("Load() scalars-go-out = %s, want %s", cfg.Targets[0].ScalarsGoOut, want)
+			}
+		})
+	}
+}
+
+// TestSelectTargets is a unit test for the SelectTargets method.
+func TestSelectTargets(t *testing.T) {
+	cfg := &Config{Targets: []Target{{Name: "a"}, {Name: "b"}}}
+	if targets, err := cfg.SelectTargets(""); err != nil || len(targets) != 2 {
+		t.Errorf("SelectTargets(\"\") = %v, %v, want all the targets", targets, err)
+	}
+	if targets, err := cfg.SelectTargets("b"); err != nil || len(targets) != 1 || targets[0].Name != "b" {
+		t.Errorf("SelectTargets(\"b\") = %v, %v, want target b", targets, err)
+	}
+	if _, err := cfg.SelectTargets("c"); err == nil {
+		t.Errorf("SelectTargets(\"c\") error = nil, want an error")
+	}
+}
+
+// TestParseScalarModel is a unit test for the ParseScalarModel function.
+func TestParseScalarModel(t *testing.T) {
+	scalar, goType, err := ParseScalarModel("BigInt=github.com/99designs/gqlgen/graphql.Int64")
+	if err != nil || scalar != "BigInt" || goType != "github.com/99designs/gqlgen/graphql.Int64" {
+		t.Errorf("ParseScalarModel() = %s, %s, %v", scalar, goType, err)
+	}
+	for _, invalid := range []string{"BigInt", "=x.Y", "BigInt="} {
+		if _, _, err := ParseScalarModel(invalid); err == nil {
+			t.Errorf("ParseScalarModel(%q) error = nil, want an error", invalid)
+		}
+	}
+}
+
+// TestWriteLoad is a unit test writing a configuration with the Write function and loading it back with Load.
+func TestWriteLoad(t *testing.T) {
+	ignored := "-"
+	convertOpts := conversion.ConvertOptions{IntPolicy: conversion.IntPolicyStrict, TypeMappings: map[string]string{"time.Time": "DateTime"}}
+	printOpts := conversion.PrettyPrintOptions{
+		UseJsonTags:      true,
+		TagFieldToIgnore: &ignored,
+		RequireTags:      conversion.SpecTagRequire{Key: "validate", Val: "required"},
+		Order:            conversion.OrderSource,
+	}
+	filePath := filepath.Join(t.TempDir(), FileName)
+	err := Write(filePath, &Config{Targets: []Target{NewTarget("default", "/src/...", convertOpts, printOpts, "/out", "package", false)}})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	cfg, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	target := cfg.Targets[0]
+	gotConvertOpts := target.ConvertOptions()
+	gotPrintOpts, _ := target.PrettyPrintOptions()
+	if target.Src != "/src/..." || target.Out != "/out" || target.Split != "package" {
+		t.Errorf("Load() target = %+v", target)
+	}
+	if gotConvertOpts.IntPolicy != convertOpts.IntPolicy || gotConvertOpts.TypeMappings["time.Time"] != "DateTime" {
+		t.Errorf("ConvertOptions() = %+v, want %+v", gotConvertOpts, convertOpts)
+	}
+	if !gotPrintOpts.UseJsonTags || *gotPrintOpts.TagFieldToIgnore != ignored || gotPrintOpts.RequireTags != printOpts.RequireTags || gotPrintOpts.Order != printOpts.Order {
+		t.Errorf("PrettyPrintOptions() = %+v, want %+v", gotPrintOpts, printOpts)
+	}
+}
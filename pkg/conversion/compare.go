@@ -0,0 +1,269 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaChange represents a change between two versions of a GqlSchema.
+type SchemaChange struct {
+	Breaking bool   // Breaking is true if the change can break the clients of the previous version
+	Kind     string // Kind is the kind of the change, see ChangeTypeRemoved and the other kinds
+	Path     string // Path is the name of the type or scalar changed, followed by the name of the field for a field
+	Message  string // Message describes the change
+}
+
+// Kinds of the SchemaChange
+const (
+	ChangeTypeAdded        = "type-added"
+	ChangeTypeRemoved      = "type-removed"
+	ChangeTypeKindChanged  = "type-kind-changed"
+	ChangeScalarAdded      = "scalar-added"
+	ChangeScalarRemoved    = "scalar-removed"
+	ChangeFieldAdded       = "field-added"
+	ChangeFieldRemoved     = "field-removed"
+	ChangeFieldTypeChanged = "field-type-changed"
+	ChangeArgumentAdded    = "argument-added"
+	ChangeArgumentRemoved  = "argument-removed"
+	ChangeArgumentChanged  = "argument-type-changed"
+)
+
+// rootOperationTypes are the names of the root operation types, which are written by hand rather than generated.
+var rootOperationTypes = map[string]bool{"Query": true, "Mutation": true, "Subscription": true}
+
+// String returns the SchemaChange as a line of a report, e.g. "BREAKING field-removed Article.title: field removed".
+func (c SchemaChange) String() string {
+	classification := "SAFE"
+	if c.Breaking {
+		classification = "BREAKING"
+	}
+	return fmt.Sprintf("%-8s %s %s: %s", classification, c.Kind, c.Path, c.Message)
+}
+
+// SchemaChanges represents the changes between two versions of a GqlSchema.
+type SchemaChanges []SchemaChange
+
+// HasBreaking returns true if any change is breaking.
+func (changes SchemaChanges) HasBreaking() bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareSchemas returns the changes turning the GqlSchema previous into the GqlSchema current, classified as breaking
+// or safe for the clients of previous:
+// - removing a type, a scalar or a field, or changing the kind of a type, is breaking
+// - adding a type or a scalar, or a field to an object type, is safe. Adding a field to an input object type is only
+// safe if the field is nullable
+// - changing the type of a field is breaking, unless only its nullability changes: an output field can become
+// non-null, and an input field can become nullable
+// - removing an argument of a field, or adding a required one, i.e. non-null without default value, is breaking.
+// Changing the type of an argument is breaking, unless it only becomes nullable, like an input field
+//
+// The root operation types (Query, Mutation and Subscription) of previous, written by hand, are not compared.
+// A type extension, e.g. extend type Query, is compared with the extension of the same type in the other version
+// sharing a field with it, rather than with the type it extends: the fields of an extension of current missing from
+// previous are added, and the extensions of previous without counterpart, e.g. written by hand, are not compared.
+// The changes are in the order of the types and fields of previous, followed by the ones added to current.
+func CompareSchemas(previous *GqlSchema, current *GqlSchema) SchemaChanges {
+	var changes SchemaChanges
+
+	currentTypes := make(map[string]GqlSchemaType, len(current.Types))
+	for _, schemaType := range current.Types {
+		if !schemaType.Extend {
+			currentTypes[schemaType.Name] = schemaType
+		}
+	}
+	previousTypes := make(map[string]bool, len(previous.Types))
+	comparedExtensions := make(map[int]bool)
+	for _, previousType := range previous.Types {
+		if previousType.Extend {
+			if idx := matchingExtension(current.Types, previousType, comparedExtensions); idx >= 0 {
+				comparedExtensions[idx] = true
+				changes = append(changes, compareFields(previousType, current.Types[idx])...)
+			}
+			continue
+		}
+		previousTypes[previousType.Name] = true
+		currentType, ok := currentTypes[previousType.Name]
+		switch {
+		case !ok && rootOperationTypes[previousType.Name]:
+		case !ok:
+			changes = append(changes, SchemaChange{Breaking: true, Kind: ChangeTypeRemoved, Path: previousType.Name, Message: "type removed"})
+		case previousType.IsInput() != currentType.IsInput():
+			changes = append(changes, SchemaChange{
+				Breaking: true,
+				Kind:     ChangeTypeKindChanged,
+				Path:     previousType.Name,
+				Message:  fmt.Sprintf("kind changed from %s to %s", kindKeyword(previousType), kindKeyword(currentType)),
+			})
+		default:
+			changes = append(changes, compareFields(previousType, currentType)...)
+		}
+	}
+	for idx, currentType := range current.Types {
+		switch {
+		case currentType.Extend && !comparedExtensions[idx]:
+			changes = append(changes, compareFields(GqlSchemaType{Name: currentType.Name, Kind: currentType.Kind}, currentType)...)
+		case !currentType.Extend && !previousTypes[currentType.Name]:
+			changes = append(changes, SchemaChange{Kind: ChangeTypeAdded, Path: currentType.Name, Message: kindKeyword(currentType) + " added"})
+		}
+	}
+
+	currentScalars := make(map[string]bool, len(current.Scalars))
+	for _, scalar := range current.Scalars {
+		currentScalars[scalar.Name] = true
+	}
+	previousScalars := make(map[string]bool, len(previous.Scalars))
+	for _, scalar := range previous.Scalars {
+		previousScalars[scalar.Name] = true
+		if !currentScalars[scalar.Name] {
+			changes = append(changes, SchemaChange{Breaking: true, Kind: ChangeScalarRemoved, Path: scalar.Name, Message: "scalar removed"})
+		}
+	}
+	for _, scalar := range current.Scalars {
+		if !previousScalars[scalar.Name] {
+			changes = append(changes, SchemaChange{Kind: ChangeScalarAdded, Path: scalar.Name, Message: "scalar added"})
+		}
+	}
+	return changes
+}
+
+// matchingExtension returns the index of the first extension of types, not compared yet, extending the same type as the
+// extension and sharing a field with it, or -1 if there is none.
+func matchingExtension(types []GqlSchemaType, extension GqlSchemaType, compared map[int]bool) int {
+	fields := make(map[string]bool, len(extension.Fields))
+	for _, field := range extension.Fields {
+		fields[field.Name] = true
+	}
+	for idx, schemaType := range types {
+		if !schemaType.Extend || compared[idx] || schemaType.Name != extension.Name || schemaType.IsInput() != extension.IsInput() {
+			continue
+		}
+		for _, field := range schemaType.Fields {
+			if fields[field.Name] {
+				return idx
+			}
+		}
+	}
+	return -1
+}
+
+// compareFields returns the changes of the fields of a type of the same kind in both versions.
+func compareFields(previousType GqlSchemaType, currentType GqlSchemaType) SchemaChanges {
+	var changes SchemaChanges
+	input := currentType.IsInput()
+
+	currentFields := make(map[string]GqlSchemaField, len(currentType.Fields))
+	for _, field := range currentType.Fields {
+		currentFields[field.Name] = field
+	}
+	previousFields := make(map[string]bool, len(previousType.Fields))
+	for _, previousField := range previousType.Fields {
+		previousFields[previousField.Name] = true
+		path := previousType.Name + "." + previousField.Name
+		currentField, ok := currentFields[previousField.Name]
+		if !ok {
+			changes = append(changes, SchemaChange{Breaking: true, Kind: ChangeFieldRemoved, Path: path, Message: "field removed"})
+			continue
+		}
+		previousRef, currentRef := previousField.typeRef(), currentField.typeRef()
+		if previousRef != currentRef {
+			changes = append(changes, SchemaChange{
+				Breaking: !isSafeTypeChange(previousRef, currentRef, input),
+				Kind:     ChangeFieldTypeChanged,
+				Path:     path,
+				Message:  fmt.Sprintf("type changed from %s to %s", previousRef, currentRef),
+			})
+		}
+		changes = append(changes, compareArguments(path, previousField.Arguments, currentField.Arguments)...)
+	}
+	for _, currentField := range currentType.Fields {
+		if previousFields[currentField.Name] {
+			continue
+		}
+		change := SchemaChange{Kind: ChangeFieldAdded, Path: currentType.Name + "." + currentField.Name, Message: "field added"}
+		if input && currentField.NonNull {
+			change.Breaking = true
+			change.Message = "non-null field added to an input type"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// compareArguments returns the changes of the arguments of the field at path in both versions.
+func compareArguments(path string, previous []GqlSchemaArgument, current []GqlSchemaArgument) SchemaChanges {
+	var changes SchemaChanges
+	currentArgs := make(map[string]GqlSchemaArgument, len(current))
+	for _, arg := range current {
+		currentArgs[arg.Name] = arg
+	}
+	previousArgs := make(map[string]bool, len(previous))
+	for _, previousArg := range previous {
+		previousArgs[previousArg.Name] = true
+		argPath := path + "(" + previousArg.Name + ")"
+		currentArg, ok := currentArgs[previousArg.Name]
+		switch {
+		case !ok:
+			changes = append(changes, SchemaChange{Breaking: true, Kind: ChangeArgumentRemoved, Path: argPath, Message: "argument removed"})
+		case previousArg.Type != currentArg.Type:
+			changes = append(changes, SchemaChange{
+				Breaking: !isSafeTypeChange(previousArg.Type, currentArg.Type, true),
+				Kind:     ChangeArgumentChanged,
+				Path:     argPath,
+				Message:  fmt.Sprintf("type changed from %s to %s", previousArg.Type, currentArg.Type),
+			})
+		}
+	}
+	for _, currentArg := range current {
+		if previousArgs[currentArg.Name] {
+			continue
+		}
+		change := SchemaChange{Kind: ChangeArgumentAdded, Path: path + "(" + currentArg.Name + ")", Message: "argument added"}
+		if strings.HasSuffix(currentArg.Type, "!") && currentArg.DefaultValue == "" {
+			change.Breaking = true
+			change.Message = "required argument added"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// typeRef returns the type reference of the field, e.g. [String]!.
+func (f GqlSchemaField) typeRef() string {
+	if f.NonNull {
+		return f.Type + "!"
+	}
+	return f.Type
+}
+
+// kindKeyword returns the keyword defining the kind of the type, e.g. type or input.
+func kindKeyword(t GqlSchemaType) string {
+	if t.Kind == "" {
+		return KindObject
+	}
+	return t.Kind
+}
+
+// isSafeTypeChange returns true if changing the type reference previous into current cannot break the clients:
+// both are the same named type, lists of the same depth, and only the nullability changes, from nullable to non-null
+// for an output field or from non-null to nullable for an input field.
+func isSafeTypeChange(previous string, current string, input bool) bool {
+	previousNonNull, currentNonNull := strings.HasSuffix(previous, "!"), strings.HasSuffix(current, "!")
+	if previousNonNull != currentNonNull && input == currentNonNull {
+		return false
+	}
+	previous, current = strings.TrimSuffix(previous, "!"), strings.TrimSuffix(current, "!")
+	previousList, currentList := strings.HasPrefix(previous, "["), strings.HasPrefix(current, "[")
+	if previousList != currentList {
+		return false
+	}
+	if previousList {
+		return isSafeTypeChange(previous[1:len(previous)-1], current[1:len(current)-1], input)
+	}
+	return previous == current
+}
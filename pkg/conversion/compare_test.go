@@ -0,0 +1,130 @@
+package conversion
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCompareSchemas is a unit test for the CompareSchemas function.
+func TestCompareSchemas(t *testing.T) {
+	previous := &GqlSchema{
+		Scalars: []GqlSchemaScalar{{Name: "BigInt"}, {Name: "Time"}},
+		Types: []GqlSchemaType{
+			{Name: "Query", Fields: []GqlSchemaField{{Name: "articles", Type: "[Article]"}}},
+			{Name: "Article", Fields: []GqlSchemaField{
+				{Name: "id", Type: "Int", NonNull: true},
+				{Name: "comments", Type: "[Comment]", Arguments: []GqlSchemaArgument{
+					{Name: "first", Type: "Int!"},
+					{Name: "after", Type: "String"},
+					{Name: "order", Type: "String"},
+					{Name: "last", Type: "Int"},
+				}},
+				{Name: "title", Type: "String"},
+				{Name: "views", Type: "BigInt"},
+				{Name: "tags", Type: "[String]"},
+				{Name: "summary", Type: "String", NonNull: true},
+				{Name: "created_at", Type: "Time"},
+			}},
+			{Name: "ArticleInput", Kind: KindInput, Fields: []GqlSchemaField{
+				{Name: "title", Type: "String"},
+				{Name: "tags", Type: "[String!]", NonNull: true},
+			}},
+			{Name: "Comment", Fields: []GqlSchemaField{{Name: "content", Type: "String"}}},
+			{Name: "User", Fields: []GqlSchemaField{{Name: "name", Type: "String"}}},
+		},
+	}
+	current := &GqlSchema{
+		Scalars: []GqlSchemaScalar{{Name: "BigInt"}, {Name: "Email"}},
+		Types: []GqlSchemaType{
+			{Name: "Article", Fields: []GqlSchemaField{
+				{Name: "id", Type: "Int", NonNull: true},
+				{Name: "comments", Type: "[Comment]", Arguments: []GqlSchemaArgument{
+					{Name: "first", Type: "Int"},
+					{Name: "after", Type: "ID"},
+					{Name: "last", Type: "Int"},
+					{Name: "before", Type: "String"},
+					{Name: "limit", Type: "Int!", DefaultValue: "10"},
+					{Name: "filter", Type: "String!"},
+				}},
+				{Name: "title", Type: "String", NonNull: true},
+				{Name: "views", Type: "Int"},
+				{Name: "tags", Type: "[String!]"},
+				{Name: "summary", Type: "String"},
+				{Name: "author", Type: "User"},
+			}},
+			{Name: "ArticleInput", Kind: KindInput, Fields: []GqlSchemaField{
+				{Name: "title", Type: "String", NonNull: true},
+				{Name: "tags", Type: "[String]"},
+				{Name: "draft", Type: "Boolean"},
+				{Name: "author", Type: "String", NonNull: true},
+			}},
+			{Name: "User", Kind: KindInput, Fields: []GqlSchemaField{{Name: "name", Type: "String"}}},
+			{Name: "Tag", Fields: []GqlSchemaField{{Name: "name", Type: "String"}}},
+		},
+	}
+	want := []string{
+		"SAFE     argument-type-changed Article.comments(first): type changed from Int! to Int",
+		"BREAKING argument-type-changed Article.comments(after): type changed from String to ID",
+		"BREAKING argument-removed Article.comments(order): argument removed",
+		"SAFE     argument-added Article.comments(before): argument added",
+		"SAFE     argument-added Article.comments(limit): argument added",
+		"BREAKING argument-added Article.comments(filter): required argument added",
+		"SAFE     field-type-changed Article.title: type changed from String to String!",
+		"BREAKING field-type-changed Article.views: type changed from BigInt to Int",
+		"SAFE     field-type-changed Article.tags: type changed from [String] to [String!]",
+		"BREAKING field-type-changed Article.summary: type changed from String! to String",
+		"BREAKING field-removed Article.created_at: field removed",
+		"SAFE     field-added Article.author: field added",
+		"BREAKING field-type-changed ArticleInput.title: type changed from String to String!",
+		"SAFE     field-type-changed ArticleInput.tags: type changed from [String!]! to [String]",
+		"SAFE     field-added ArticleInput.draft: field added",
+		"BREAKING field-added ArticleInput.author: non-null field added to an input type",
+		"BREAKING type-removed Comment: type removed",
+		"BREAKING type-kind-changed User: kind changed from type to input",
+		"SAFE     type-added Tag: type added",
+		"BREAKING scalar-removed Time: scalar removed",
+		"SAFE     scalar-added Email: scalar added",
+	}
+
+	changes := CompareSchemas(previous, current)
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("CompareSchemas() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !changes.HasBreaking() {
+		t.Errorf("HasBreaking() = false, want true")
+	}
+	if CompareSchemas(current, current) != nil {
+		t.Errorf("CompareSchemas() of a schema with itself = %v, want no change", CompareSchemas(current, current))
+	}
+}
+
+// TestCompareSchemasExtensions is a unit test for CompareSchemas comparing the type extensions.
+func TestCompareSchemasExtensions(t *testing.T) {
+	previous := &GqlSchema{Types: []GqlSchemaType{
+		{Name: "Query", Fields: []GqlSchemaField{{Name: "hello", Type: "String"}}},
+		{Name: "Query", Extend: true, Fields: []GqlSchemaField{{Name: "me", Type: "String"}}},
+		{Name: "Query", Extend: true, Fields: []GqlSchemaField{{Name: "article", Type: "String"}, {Name: "articles", Type: "[String]"}}},
+	}}
+	current := &GqlSchema{Types: []GqlSchemaType{
+		{Name: "Query", Extend: true, Fields: []GqlSchemaField{{Name: "article", Type: "String"}, {Name: "user", Type: "String"}}},
+		{Name: "Mutation", Extend: true, Fields: []GqlSchemaField{{Name: "createArticle", Type: "String"}}},
+	}}
+	// The hand-written Query and its extension me are not compared
+	want := []string{
+		"BREAKING field-removed Query.articles: field removed",
+		"SAFE     field-added Query.user: field added",
+		"SAFE     field-added Mutation.createArticle: field added",
+	}
+
+	var got []string
+	for _, change := range CompareSchemas(previous, current) {
+		got = append(got, change.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("CompareSchemas() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
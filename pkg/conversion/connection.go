@@ -0,0 +1,139 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/structtag"
+)
+
+// Policies for the Relay connections, see https://relay.dev/graphql/connections.htm
+const (
+	// ConnectionsTagged replaces the fields tagged with ConnectionTagValue by a connection
+	ConnectionsTagged = "tagged"
+	// ConnectionsAll replaces every field listing objects by a connection
+	ConnectionsAll = "all"
+)
+
+// GqlTag is the key of the struct tag giving instructions to the conversion, e.g. `gql:"connection"`.
+const GqlTag = "gql"
+
+// ConnectionTagValue is the value of the GqlTag replacing a field listing objects by a connection.
+const ConnectionTagValue = "connection"
+
+// PageInfoType is the name of the type describing a page of a connection, shared by the connections of the schema.
+const PageInfoType = "PageInfo"
+
+// connectionArguments are the pagination arguments of the fields whose type is a connection.
+var connectionArguments = []GqlSchemaArgument{
+	{Name: "first", Type: "Int"},
+	{Name: "after", Type: "String"},
+	{Name: "last", Type: "Int"},
+	{Name: "before", Type: "String"},
+}
+
+// validateConnectionsPolicy returns an error if the connections policy is not valid.
+func validateConnectionsPolicy(policy string) error {
+	switch policy {
+	case "", ConnectionsTagged, ConnectionsAll:
+		return nil
+	}
+	return fmt.Errorf("%v: connections policy %q is neither %s nor %s", InvalidOptionErr, policy, ConnectionsTagged, ConnectionsAll)
+}
+
+// hasGqlTagValue returns true if the GqlTag of a field lists the value, e.g. `gql:"connection"`.
+func hasGqlTagValue(tags *structtag.Tags, value string) bool {
+	gqlTag, err := tags.Get(GqlTag)
+	if err != nil {
+		return false
+	}
+	return gqlTag.Name == value || gqlTag.HasOption(value)
+}
+
+// applyConnections replaces the fields of the object types listing objects, the ones tagged with ConnectionTagValue or
+// all of them with ConnectionsAll, by a Relay connection taking the first, after, last and before arguments. The
+// XConnection and XEdge types of each object X listed, and the PageInfoType, are added to the schema. A field tagged
+// with ConnectionTagValue that does not list objects is an error.
+func (r *schemaResolver) applyConnections() error {
+	objects := make(map[string]bool)
+	for _, schemaType := range r.types {
+		if !schemaType.IsInput() {
+			objects[schemaType.Name] = true
+		}
+	}
+
+	type connection struct{ node, pkg string }
+	var connections []connection
+	for idx := range r.types {
+		schemaType := &r.types[idx]
+		if schemaType.IsInput() {
+			continue
+		}
+		for fieldIdx := range schemaType.Fields {
+			field := &schemaType.Fields[fieldIdx]
+			tagged := r.connectionFields[schemaType.Name+"."+field.Name]
+			if !tagged && r.opts.Connections != ConnectionsAll {
+				continue
+			}
+			node, isList := listedType(field.Type)
+			if !isList || !objects[node] {
+				if tagged {
+					return fmt.Errorf("field %s of type %s is tagged %s:%q but does not list objects", field.Name, schemaType.Name, GqlTag, ConnectionTagValue)
+				}
+				continue
+			}
+			field.Type = node + "Connection"
+			field.Arguments = connectionArguments
+			connections = append(connections, connection{node: node, pkg: schemaType.Package})
+		}
+	}
+	for _, connection := range connections {
+		r.connect(connection.node, connection.pkg)
+	}
+	return nil
+}
+
+// connect adds the XConnection and XEdge types of the object type node to the schema, in the package pkg, unless
+// already added, as well as the PageInfoType the first time.
+func (r *schemaResolver) connect(node string, pkg string) {
+	if r.connected[node] {
+		return
+	}
+	r.connected[node] = true
+	r.types = append(r.types, connectionTypeDefinitions(node, pkg)...)
+	if len(r.connected) == 1 {
+		r.types = append(r.types, GqlSchemaType{Name: PageInfoType, Package: pkg, Fields: []GqlSchemaField{
+			{Name: "hasNextPage", Type: "Boolean", NonNull: true},
+			{Name: "hasPreviousPage", Type: "Boolean", NonNull: true},
+			{Name: "startCursor", Type: "String"},
+			{Name: "endCursor", Type: "String"},
+		}})
+	}
+}
+
+// connectionTypeDefinitions returns the XConnection and XEdge types of the object type node, in the package pkg.
+func connectionTypeDefinitions(node string, pkg string) []GqlSchemaType {
+	return []GqlSchemaType{
+		{Name: node + "Connection", Package: pkg, Fields: []GqlSchemaField{
+			{Name: "edges", Type: "[" + node + "Edge]"},
+			{Name: "pageInfo", Type: PageInfoType, NonNull: true},
+		}},
+		{Name: node + "Edge", Package: pkg, Fields: []GqlSchemaField{
+			{Name: "node", Type: node},
+			{Name: "cursor", Type: "String", NonNull: true},
+		}},
+	}
+}
+
+// listedType returns the named type listed by a GraphQL list type, e.g. Article for [Article!], and true, or false if
+// the type is not a list of a named type.
+func listedType(gqlType string) (string, bool) {
+	if !strings.HasPrefix(gqlType, "[") || !strings.HasSuffix(gqlType, "]") {
+		return "", false
+	}
+	elem := strings.TrimSuffix(gqlType[1:len(gqlType)-1], "!")
+	if strings.ContainsAny(elem, "[]!") {
+		return "", false
+	}
+	return elem, true
+}
@@ -0,0 +1,112 @@
+package conversion
+
+import "testing"
+
+// TestConnections is a unit test for the Relay connections of ResolveGqlSchema.
+func TestConnections(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "Comment", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Body", GqlFieldType: "String", GqlFieldTags: `json:"body"`}}},
+		{GqlTypeName: "Post", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "Comments", GqlFieldType: "[Comment]", GqlFieldTags: `json:"comments" gql:"connection"`},
+			{GqlFieldName: "Related", GqlFieldType: "[Post]", GqlFieldTags: `json:"related"`},
+			{GqlFieldName: "Tags", GqlFieldType: "[String]", GqlFieldTags: `json:"tags"`},
+		}},
+	}
+	comment := "type Comment {\n  body: String\n}\n\n"
+	commentConnection := "type CommentConnection {\n  edges: [CommentEdge]\n  pageInfo: PageInfo!\n}\n\n" +
+		"type CommentEdge {\n  node: Comment\n  cursor: String!\n}\n\n"
+	pageInfo := "type PageInfo {\n  hasNextPage: Boolean!\n  hasPreviousPage: Boolean!\n  startCursor: String\n  endCursor: String\n}\n\n"
+	arguments := "(first: Int, after: String, last: Int, before: String)"
+	tests := []struct {
+		name    string
+		policy  string
+		defs    []GqlTypeDefinition
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Tagged",
+			want: "\n" + comment + commentConnection + pageInfo +
+				"type Post {\n  comments" + arguments + ": CommentConnection\n  related: [Post]\n  tags: [String]\n}\n\n",
+		},
+		{
+			// The lists of scalars are left as is
+			name:   "All",
+			policy: ConnectionsAll,
+			want: "\n" + comment + commentConnection + pageInfo + "type Post {\n  comments" + arguments + ": CommentConnection\n" +
+				"  related" + arguments + ": PostConnection\n  tags: [String]\n}\n\n" +
+				"type PostConnection {\n  edges: [PostEdge]\n  pageInfo: PageInfo!\n}\n\ntype PostEdge {\n  node: Post\n  cursor: String!\n}\n\n",
+		},
+		{
+			name: "TaggedNotList",
+			defs: []GqlTypeDefinition{{GqlTypeName: "Post", GqlFields: []GqlFieldsDefinition{
+				{GqlFieldName: "Title", GqlFieldType: "String", GqlFieldTags: `gql:"connection"`},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "TaggedScalars",
+			defs: []GqlTypeDefinition{{GqlTypeName: "Post", GqlFields: []GqlFieldsDefinition{
+				{GqlFieldName: "Tags", GqlFieldType: "[String]", GqlFieldTags: `gql:"connection"`},
+			}}},
+			wantErr: true,
+		},
+		{name: "InvalidPolicy", policy: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs := gqlTypeDefs
+			if tt.defs != nil {
+				defs = tt.defs
+			}
+			schema, err := ResolveGqlSchema(defs, &PrettyPrintOptions{UseJsonTags: true, Connections: tt.policy})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveGqlSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && schema.String() != tt.want {
+				t.Errorf("ResolveGqlSchema() = %q, want %q", schema.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestConnectionsGoModelDirectives is a unit test for the gqlgen directive of the connections of ResolveGqlSchema.
+func TestConnectionsGoModelDirectives(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "Post", GqlTypePackage: "github.com/acme/models", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "Related", GqlFieldType: "[Post]", GqlFieldTags: `json:"related" gql:"connection"`},
+		}},
+	}
+	schema, err := ResolveGqlSchema(gqlTypeDefs, &PrettyPrintOptions{UseJsonTags: true, GoModelDirectives: true, Order: OrderSource})
+	if err != nil {
+		t.Fatalf("ResolveGqlSchema() error = %v", err)
+	}
+	// The connection is resolved rather than bound to the Go field
+	want := "  related(first: Int, after: String, last: Int, before: String): PostConnection @goField(forceResolver: true)\n"
+	if got := schema.Types[0].Fields[0].String(); got != want {
+		t.Errorf("ResolveGqlSchema() field = %q, want %q", got, want)
+	}
+}
+
+// TestListedType is a unit test for the listedType function.
+func TestListedType(t *testing.T) {
+	tests := []struct {
+		gqlType  string
+		want     string
+		wantList bool
+	}{
+		{gqlType: "[Article]", want: "Article", wantList: true},
+		{gqlType: "[Article!]", want: "Article", wantList: true},
+		{gqlType: "Article"},
+		{gqlType: "[[Article]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.gqlType, func(t *testing.T) {
+			got, isList := listedType(tt.gqlType)
+			if got != tt.want || isList != tt.wantList {
+				t.Errorf("listedType() = %s, %v, want %s, %v", got, isList, tt.want, tt.wantList)
+			}
+		})
+	}
+}
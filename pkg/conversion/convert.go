@@ -4,6 +4,7 @@
 package conversion
 
 import (
+	"errors"
 	"fmt"
 	"github.com/VintageOps/structogqlgen/pkg/load"
 	"go/token"
@@ -12,8 +13,12 @@ import (
 
 // GqlTypeDefinition contains the definition of a graphQl Type
 type GqlTypeDefinition struct {
-	GqlTypeName string                // GqlTypeName is the name of a graphQL type.
-	GqlFields   []GqlFieldsDefinition // GqlFields is a slice of GqlFieldsDefinition, which represents the fields of a GraphQL type.
+	GqlTypeName       string                // GqlTypeName is the name of a graphQL type.
+	GqlFields         []GqlFieldsDefinition // GqlFields is a slice of GqlFieldsDefinition, which represents the fields of a GraphQL type.
+	GqlTypePosition   token.Position        // GqlTypePosition is the position of the struct declaration in the Go source, if known.
+	GqlTypePackage    string                // GqlTypePackage is the import path of the Go package declaring the struct, if any.
+	GqlTypeGoType     types.Type            // GqlTypeGoType is the Go named type of the struct, if declared in a package.
+	GqlTypeDirectives []string              // GqlTypeDirectives are the directives of the doc comment of the struct, e.g. entity, see DocDirectivePrefix.
 }
 
 // GqlFieldsDefinition represents the definition of a GraphQL field.
@@ -21,8 +26,13 @@ type GqlFieldsDefinition struct {
 	GqlFieldName         string                // GqlFieldName represents the name of a graphQL field
 	GqlFieldType         string                // GqlFieldType is a string representing the type of GraphQL field
 	GqlFieldTags         string                // GqlFieldTags represents the tags of a GraphQL field
+	GqlFieldPosition     token.Position        // GqlFieldPosition is the position of the struct field declaration in the Go source, if known.
 	GqlFieldIsEmbedded   bool                  // GqlFieldIsEmbedded represents whether a GraphQL field is an embedded field.
 	IsCustomScalar       bool                  // IsCustomScalar is True if this field need to define a Scalar which will be type Name
+	IsBasicKind          bool                  // IsBasicKind is True if the Go type, ignoring a pointer, is a boolean, integer, float or string
+	GqlFieldMarshaler    string                // GqlFieldMarshaler is the marshaler interface implemented by the Go type that decided the GraphQL type, if any
+	GqlFieldGoType       string                // GqlFieldGoType is the Go named type, qualified by its import path, converted into the custom scalar, if any
+	GqlFieldScalarGoType types.Type            // GqlFieldScalarGoType is the Go type converted into the custom scalar, if any, including the basic and mapped types
 	NestedCustomType     []GqlTypeDefinition   // NestedCustomType represents any custom types that might be needed to be defined for this type.
 	GqlGenFieldsEmbedded []GqlFieldsDefinition // GqlGenFieldsEmbedded represents fields for Embedded Structs
 }
@@ -71,111 +81,231 @@ func (e ConvertCustomError) Error() string {
 }
 
 // InvalidTypeErr represents an error indicating an invalid type.
+// InvalidOptionErr represents an error indicating an invalid conversion option.
 const (
-	InvalidTypeErr = ConvertCustomError("invalid type")
+	InvalidTypeErr   = ConvertCustomError("invalid type")
+	InvalidOptionErr = ConvertCustomError("invalid option")
 )
 
-// BuildGqlTypes builds an array of GqlTypeDefinitions for a given array of struct definitions.
+// BuildGqlTypes builds an array of GqlTypeDefinitions for a given array of struct definitions, using the default ConvertOptions.
 // It calls BuildGqlgenType for each struct definition and populates the array with the results.
-// If any error occurs during the process, it returns the error immediately.
+// If any field cannot be converted, it returns the Diagnostics of all such fields as error.
 func BuildGqlTypes(structsFound []load.StructDiscovered) ([]GqlTypeDefinition, error) {
-	gqlGenTypes := make([]GqlTypeDefinition, len(structsFound))
-	for idx, structType := range structsFound {
-		var err error
-		gqlGenTypes[idx], err = BuildGqlgenType(structType)
-		if err != nil {
-			return nil, err
+	gqlGenTypes, _, err := BuildGqlTypesWithOptions(structsFound, &ConvertOptions{})
+	return gqlGenTypes, err
+}
+
+// BuildGqlTypesWithOptions builds an array of GqlTypeDefinitions for a given array of struct definitions,
+// converting the Go types according to the provided ConvertOptions.
+// It returns the Diagnostics collected while converting all the structs, e.g. for the fields skipped because of their
+// unsupported type. If any Diagnostic is an error, it returns no GqlTypeDefinition and those Diagnostics as error.
+// When marshalers detection is enabled, the structs implementing a marshaler get no GqlTypeDefinition, as the fields of
+// their type are converted according to that marshaler.
+func BuildGqlTypesWithOptions(structsFound []load.StructDiscovered, opts *ConvertOptions) ([]GqlTypeDefinition, Diagnostics, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+	c := newConverter(opts)
+	gqlGenTypes := make([]GqlTypeDefinition, 0, len(structsFound))
+	for _, structType := range structsFound {
+		if c.isMarshalerStruct(structType) {
+			continue
 		}
+		gqlGenTypes = append(gqlGenTypes, c.buildGqlgenType(structType))
 	}
-	return gqlGenTypes, nil
+	if err := c.diagnostics.Err(); err != nil {
+		return nil, c.diagnostics, err
+	}
+	return gqlGenTypes, c.diagnostics, nil
 }
 
-// BuildGqlgenType builds a GqlTypeDefinition for a given struct definition.
+// BuildGqlgenType builds a GqlTypeDefinition for a given struct definition, using the default ConvertOptions.
 // It converts the struct fields into GqlFieldsDefinition, populating the field name and tags.
 // It also determines the field type by invoking ConvertType and handles any custom types or scalars.
 func BuildGqlgenType(structDef load.StructDiscovered) (GqlTypeDefinition, error) {
+	c := newConverter(&ConvertOptions{})
+	gqlTypeDef := c.buildGqlgenType(structDef)
+	return gqlTypeDef, c.diagnostics.Err()
+}
+
+// ConvertType converts a Go type into a GqlFieldsDefinition by performing type-specific conversions, using the default ConvertOptions.
+// It handles basic types, slices, pointers, maps, named types, and interfaces. .
+func ConvertType(goType types.Type, gqlFieldDef *GqlFieldsDefinition) error {
+	return newConverter(&ConvertOptions{}).convertType(goType, gqlFieldDef)
+}
+
+// converter converts Go types into GraphQL types according to a set of ConvertOptions.
+// It keeps track of the struct and field being converted to report Diagnostics.
+type converter struct {
+	opts        *ConvertOptions
+	fset        *token.FileSet
+	context     conversionContext
+	diagnostics Diagnostics
+	unwrapping  map[*types.Named]bool // unwrapping are the named slices, maps and pointers being unwrapped
+}
+
+// conversionContext represents the struct field being converted.
+type conversionContext struct {
+	structName string
+	fieldName  string
+	pos        token.Pos
+}
+
+// newConverter returns a converter using the provided options.
+func newConverter(opts *ConvertOptions) *converter {
+	return &converter{opts: opts, unwrapping: make(map[*types.Named]bool)}
+}
+
+// buildGqlgenType builds a GqlTypeDefinition for a given struct definition.
+// The fields that cannot be converted are left out and reported as error Diagnostics.
+func (c *converter) buildGqlgenType(structDef load.StructDiscovered) GqlTypeDefinition {
 
 	var gqlTypeDef GqlTypeDefinition
 
-	gqlTypeDef.GqlTypeName = structDef.Name.Id()
-	gqlTypeDef.GqlFields = make([]GqlFieldsDefinition, structDef.Obj.NumFields())
+	if structDef.Fset != nil {
+		c.fset = structDef.Fset
+	}
+	// Nested types are built while converting a field, restore the context of that field once done
+	defer func(context conversionContext) { c.context = context }(c.context)
+
+	gqlTypeDef.GqlTypeName = structDef.Name.Name()
+	if c.fset != nil && structDef.Name.Pos().IsValid() {
+		gqlTypeDef.GqlTypePosition = c.fset.Position(structDef.Name.Pos())
+	}
+	if structDef.Name.Pkg() != nil {
+		gqlTypeDef.GqlTypePackage = structDef.Name.Pkg().Path()
+		gqlTypeDef.GqlTypeGoType = structDef.Name.Type()
+	}
+	gqlTypeDef.GqlTypeDirectives = docDirectives(structDef.Doc)
+	gqlTypeDef.GqlFields = make([]GqlFieldsDefinition, 0, structDef.Obj.NumFields())
 	for i := 0; i < structDef.Obj.NumFields(); i++ {
 		field := structDef.Obj.Field(i)
 		tags := structDef.Obj.Tag(i)
 		isEmbedded := field.Embedded()
+		c.context.structName = gqlTypeDef.GqlTypeName
+		c.context.fieldName = field.Name()
+		if field.Pos().IsValid() {
+			c.context.pos = field.Pos()
+		}
 		// Populate Field Name and Tag
-		gqlTypeDef.GqlFields[i] = GqlFieldsDefinition{GqlFieldName: field.Name(), GqlFieldTags: tags, GqlFieldIsEmbedded: isEmbedded}
+		gqlFieldDef := GqlFieldsDefinition{GqlFieldName: field.Name(), GqlFieldTags: tags, GqlFieldIsEmbedded: isEmbedded}
+		if c.fset != nil && field.Pos().IsValid() {
+			gqlFieldDef.GqlFieldPosition = c.fset.Position(field.Pos())
+		}
 		// Find Field Type and Scalars
-		err := ConvertType(field.Type(), &gqlTypeDef.GqlFields[i])
+		err := c.convertType(field.Type(), &gqlFieldDef)
+		if errors.Is(err, errSkipField) {
+			continue
+		}
 		if err != nil {
-			return gqlTypeDef, err
+			c.diagnose(SeverityError, CodeInvalidType, err.Error())
+			continue
 		}
+		gqlTypeDef.GqlFields = append(gqlTypeDef.GqlFields, gqlFieldDef)
 	}
 
-	return gqlTypeDef, nil
+	return gqlTypeDef
 }
 
-// ConvertType converts a Go type into a GqlFieldsDefinition by performing type-specific conversions.
-// It handles basic types, slices, pointers, maps, named types, and interfaces. .
-func ConvertType(goType types.Type, gqlFieldDef *GqlFieldsDefinition) error {
+// convertType converts a Go type into a GqlFieldsDefinition by performing type-specific conversions.
+// The Go type converted into a custom scalar, if any, is recorded in the GqlFieldsDefinition.
+func (c *converter) convertType(goType types.Type, gqlFieldDef *GqlFieldsDefinition) error {
+	var err error
 	switch t := goType.(type) {
 	case *types.Basic:
-		return convertBasicType(t, gqlFieldDef)
+		err = c.convertBasicType(t, gqlFieldDef)
 	case *types.Slice:
-		return convertSliceType(t, gqlFieldDef)
+		err = c.convertSliceType(t.Elem(), gqlFieldDef)
 	case *types.Pointer:
-		return convertPointerType(t, gqlFieldDef)
+		err = c.convertPointerType(t, gqlFieldDef)
 	case *types.Map:
-		return convertMapType(t, gqlFieldDef)
+		err = c.convertMapType(t, gqlFieldDef)
 	case *types.Named:
-		return convertNamedType(t, gqlFieldDef)
+		err = c.convertNamedType(t, gqlFieldDef)
 	case *types.Interface:
-		return convertInterfaceType(t, gqlFieldDef)
+		err = c.convertInterfaceType(t, gqlFieldDef)
+	case *types.Chan:
+		err = c.convertUnsupportedType(t, "Chan", gqlFieldDef)
+	case *types.Signature:
+		err = c.convertUnsupportedType(t, "Func", gqlFieldDef)
+	case *types.Array:
+		err = c.convertSliceType(t.Elem(), gqlFieldDef)
 	default:
 		return fmt.Errorf("%s: %v", InvalidTypeErr, t.String())
 	}
+	// The element type of a slice or pointer already recorded its own type
+	if err == nil && gqlFieldDef.IsCustomScalar && gqlFieldDef.GqlFieldScalarGoType == nil {
+		gqlFieldDef.GqlFieldScalarGoType = goType
+	}
+	return err
 }
 
 // convertBasicType converts a Go basic type into a GqlFieldsDefinition by mapping it to a GraphQL type.
-func convertBasicType(t *types.Basic, gqlFieldDef *GqlFieldsDefinition) error {
+// Integers are mapped according to the integer policy of the converter when one is set.
+func (c *converter) convertBasicType(t *types.Basic, gqlFieldDef *GqlFieldsDefinition) error {
 
 	if t.Kind() == types.Invalid {
 		return fmt.Errorf("%v: %s", InvalidTypeErr, t.String())
 	}
 
+	if val, ok := c.opts.integerGqlType(t.Kind()); ok {
+		gqlFieldDef.GqlFieldType = val.gqlType
+		gqlFieldDef.IsCustomScalar = val.isCustomScalar
+		gqlFieldDef.IsBasicKind = isBasicKind(t)
+		return nil
+	}
+
 	if val, ok := MapBasicKindToGqlType[t.Kind()]; ok {
+		if isUnsupportedBasic(t) && c.opts.UnsupportedPolicy != "" {
+			return c.convertUnsupportedType(t, val.gqlType, gqlFieldDef)
+		}
 		gqlFieldDef.GqlFieldType = val.gqlType
 		gqlFieldDef.IsCustomScalar = val.isCustomScalar
+		gqlFieldDef.IsBasicKind = isBasicKind(t)
 		return nil
 	}
 
 	return fmt.Errorf("%v: %s", InvalidTypeErr, t.String())
 }
 
-// convertSliceType converts a Go type representing a slice into a GqlFieldsDefinition.
-func convertSliceType(t *types.Slice, gqlFieldDef *GqlFieldsDefinition) error {
-	var sliceTypeSql GqlFieldsDefinition
-	err := ConvertType(t.Elem(), &sliceTypeSql)
+// convertSliceType converts a Go type representing a slice or an array, given the type of its elements, into a
+// GqlFieldsDefinition.
+func (c *converter) convertSliceType(elem types.Type, gqlFieldDef *GqlFieldsDefinition) error {
+	sliceTypeSql := GqlFieldsDefinition{GqlFieldName: gqlFieldDef.GqlFieldName}
+	err := c.convertType(elem, &sliceTypeSql)
 	if err != nil {
 		return err
 	}
 	gqlFieldDef.GqlFieldType = fmt.Sprintf("[%s]", sliceTypeSql.GqlFieldType)
+	// The scalar to define, if any, is the type of the list items
+	gqlFieldDef.IsCustomScalar = sliceTypeSql.IsCustomScalar
+	gqlFieldDef.GqlFieldMarshaler = sliceTypeSql.GqlFieldMarshaler
+	gqlFieldDef.GqlFieldGoType = sliceTypeSql.GqlFieldGoType
+	gqlFieldDef.GqlFieldScalarGoType = sliceTypeSql.GqlFieldScalarGoType
+	gqlFieldDef.NestedCustomType = append(gqlFieldDef.NestedCustomType, sliceTypeSql.NestedCustomType...)
 	return nil
 }
 
 // convertPointerType converts a pointer type into a GqlFieldsDefinition.
-func convertPointerType(t *types.Pointer, gqlFieldDef *GqlFieldsDefinition) error {
-	var pointerTypeSql GqlFieldsDefinition
-	err := ConvertType(t.Elem(), &pointerTypeSql)
+func (c *converter) convertPointerType(t *types.Pointer, gqlFieldDef *GqlFieldsDefinition) error {
+	pointerTypeSql := GqlFieldsDefinition{GqlFieldName: gqlFieldDef.GqlFieldName, GqlFieldIsEmbedded: gqlFieldDef.GqlFieldIsEmbedded}
+	err := c.convertType(t.Elem(), &pointerTypeSql)
 	if err != nil {
 		return err
 	}
 	gqlFieldDef.GqlFieldType = pointerTypeSql.GqlFieldType
+	gqlFieldDef.IsCustomScalar = pointerTypeSql.IsCustomScalar
+	gqlFieldDef.IsBasicKind = pointerTypeSql.IsBasicKind
+	gqlFieldDef.GqlFieldMarshaler = pointerTypeSql.GqlFieldMarshaler
+	gqlFieldDef.GqlFieldGoType = pointerTypeSql.GqlFieldGoType
+	gqlFieldDef.GqlFieldScalarGoType = pointerTypeSql.GqlFieldScalarGoType
+	gqlFieldDef.NestedCustomType = append(gqlFieldDef.NestedCustomType, pointerTypeSql.NestedCustomType...)
+	gqlFieldDef.GqlGenFieldsEmbedded = pointerTypeSql.GqlGenFieldsEmbedded
 	return nil
 }
 
 // convertMapType converts a Go map type into a GqlFieldsDefinition representing a struct.
-func convertMapType(t *types.Map, gqlFieldDef *GqlFieldsDefinition) error {
+func (c *converter) convertMapType(t *types.Map, gqlFieldDef *GqlFieldsDefinition) error {
 	newStructFieldsName := []string{"key", "values"}
 	newStructfields := []*types.Var{
 		types.NewVar(token.NoPos, nil, newStructFieldsName[0], t.Key()),
@@ -189,39 +319,80 @@ func convertMapType(t *types.Map, gqlFieldDef *GqlFieldsDefinition) error {
 	var newStructDiscManual load.StructDiscovered
 	newStructDiscManual.Name = newStruct.Obj()
 	newStructDiscManual.Obj, _ = newStruct.Underlying().(*types.Struct)
-	nestStructTypeDef, err := BuildGqlgenType(newStructDiscManual)
-	if err != nil {
-		return err
-	}
+	nestStructTypeDef := c.buildGqlgenType(newStructDiscManual)
 	gqlFieldDef.NestedCustomType = append(gqlFieldDef.NestedCustomType, nestStructTypeDef)
 	return nil
 }
 
 // convertNamedType converts a named type into a GqlFieldsDefinition.
-func convertNamedType(t *types.Named, gqlFieldDef *GqlFieldsDefinition) error {
-	if ts, ok := t.Underlying().(*types.Struct); ok {
-		gqlFieldDef.GqlFieldType = t.Obj().Id()
+// Named structs are referenced by name, named slices, maps and pointers are unwrapped to their underlying type,
+// and other named types are converted into a custom scalar, unless they are basic types that must be unwrapped.
+// A named slice, map or pointer defined in terms of itself, e.g. type Tree []Tree, is an error.
+// When marshalers detection is enabled, a named type implementing a marshaler is converted according to that marshaler.
+// A named type found in the type mappings is converted into the GraphQL type it is mapped to, before any other rule.
+func (c *converter) convertNamedType(t *types.Named, gqlFieldDef *GqlFieldsDefinition) error {
+	if mapped, ok := c.opts.mappedGqlType(t); ok && !gqlFieldDef.GqlFieldIsEmbedded {
+		gqlFieldDef.GqlFieldType = mapped.gqlType
+		gqlFieldDef.IsCustomScalar = mapped.isCustomScalar
+		return nil
+	}
+	if c.opts.DetectMarshalers && !gqlFieldDef.GqlFieldIsEmbedded {
+		if marshaler := detectMarshaler(t); marshaler != "" {
+			convertMarshalerType(t, marshaler, gqlFieldDef)
+			c.diagnose(SeverityInfo, CodeMarshaler, fmt.Sprintf("%s implemented by %s, converted into %s", marshaler, t, gqlFieldDef.GqlFieldType))
+			return nil
+		}
+	}
+	switch tu := t.Underlying().(type) {
+	case *types.Struct:
+		gqlFieldDef.GqlFieldType = t.Obj().Name()
 		// If the field is embedded, then need to populate
 		if gqlFieldDef.GqlFieldIsEmbedded {
 			var newStructDiscManual load.StructDiscovered
 			newStructDiscManual.Name = t.Obj()
-			newStructDiscManual.Obj = ts
-			nestStructTypeDef, err := BuildGqlgenType(newStructDiscManual)
-			if err != nil {
-				return err
-			}
+			newStructDiscManual.Obj = tu
+			nestStructTypeDef := c.buildGqlgenType(newStructDiscManual)
 			gqlFieldDef.GqlGenFieldsEmbedded = nestStructTypeDef.GqlFields
 		}
 		return nil
-	} else {
-		gqlFieldDef.GqlFieldType = t.Obj().Name()
-		gqlFieldDef.IsCustomScalar = true
+	case *types.Slice, *types.Array, *types.Map, *types.Pointer:
+		if c.unwrapping[t] {
+			return fmt.Errorf("%v: %s is defined in terms of itself", InvalidTypeErr, t)
+		}
+		c.unwrapping[t] = true
+		defer delete(c.unwrapping, t)
+		return c.convertType(tu, gqlFieldDef)
+	case *types.Basic:
+		// Named integers follow the integer policy, when one is set, like any other integer
+		if _, ok := c.opts.integerGqlType(tu.Kind()); ok || c.opts.UnwrapNamedBasic {
+			// The custom scalar, if any, is the one of the basic type
+			return c.convertType(tu, gqlFieldDef)
+		}
+		gqlFieldDef.IsBasicKind = isBasicKind(tu)
 	}
+	gqlFieldDef.GqlFieldType = t.Obj().Name()
+	gqlFieldDef.IsCustomScalar = true
+	gqlFieldDef.GqlFieldGoType = qualifiedTypeName(t)
 	return nil
 }
 
+// qualifiedTypeName returns the name of the named type qualified by the import path of its package, or an empty string
+// for a predeclared type, e.g. error.
+func qualifiedTypeName(t *types.Named) string {
+	if t.Obj().Pkg() == nil {
+		return ""
+	}
+	return t.Obj().Pkg().Path() + "." + t.Obj().Name()
+}
+
+// isBasicKind reports whether t is a boolean, integer, float or string type, i.e. one of the kinds
+// the `json:",string"` option applies to.
+func isBasicKind(t *types.Basic) bool {
+	return t.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0
+}
+
 // convertInterfaceType converts a *types.Interface into a GqlFieldsDefinition.
-func convertInterfaceType(t *types.Interface, gqlFieldDef *GqlFieldsDefinition) error {
+func (c *converter) convertInterfaceType(t *types.Interface, gqlFieldDef *GqlFieldsDefinition) error {
 	if t.Empty() {
 		// Empty Interface
 		gqlFieldDef.GqlFieldType = "interfaceEmpty"
@@ -1,6 +1,7 @@
 package conversion
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/VintageOps/structogqlgen/pkg/load"
@@ -64,3 +65,86 @@ func TestBuildGqlgenType(t *testing.T) {
 		})
 	}
 }
+
+// TestConvertRecursiveNamedType is a unit test for the conversion of the named types defined in terms of themselves.
+func TestConvertRecursiveNamedType(t *testing.T) {
+	recursive := func(name string, underlying func(self *types.Named) types.Type) *types.Var {
+		named := types.NewNamed(types.NewTypeName(token.NoPos, nil, name, nil), nil, nil)
+		named.SetUnderlying(underlying(named))
+		return types.NewVar(token.NoPos, nil, name, named)
+	}
+	fields := []*types.Var{
+		recursive("Tree", func(self *types.Named) types.Type { return types.NewSlice(self) }),
+		recursive("P", func(self *types.Named) types.Type { return types.NewPointer(self) }),
+		recursive("M", func(self *types.Named) types.Type { return types.NewMap(types.Typ[types.String], self) }),
+		types.NewVar(token.NoPos, nil, "Name", types.Typ[types.String]),
+	}
+	structDef := load.StructDiscovered{
+		Name: types.NewTypeName(token.NoPos, nil, "Node", nil),
+		Obj:  types.NewStruct(fields, make([]string, len(fields))),
+	}
+	_, diagnostics, err := BuildGqlTypesWithOptions([]load.StructDiscovered{structDef}, &ConvertOptions{})
+	if err == nil {
+		t.Fatal("BuildGqlTypesWithOptions() error = nil, want an error")
+	}
+	if len(diagnostics) != 3 {
+		t.Fatalf("BuildGqlTypesWithOptions() diagnostics = %v, want 3", diagnostics)
+	}
+	for idx, name := range []string{"Tree", "P", "M"} {
+		if !strings.HasSuffix(diagnostics[idx].Message, name+" is defined in terms of itself") {
+			t.Errorf("BuildGqlTypesWithOptions() diagnostic = %q, want %s defined in terms of itself", diagnostics[idx].Message, name)
+		}
+	}
+}
+
+// TestConvertNamedType is a unit test for the conversion of named types.
+func TestConvertNamedType(t *testing.T) {
+	newNamed := func(name string, underlying types.Type) types.Type {
+		return types.NewNamed(types.NewTypeName(token.NoPos, nil, name, nil), underlying, nil)
+	}
+	models := types.NewPackage("github.com/acme/models", "models")
+	tests := []struct {
+		name       string
+		opts       ConvertOptions
+		goType     types.Type
+		wantType   string
+		wantScalar bool
+		wantNested int
+		wantGoType string
+	}{
+		{name: "NamedSlice", goType: newNamed("Tags", types.NewSlice(types.Typ[types.String])), wantType: "[String]"},
+		{name: "Array", goType: types.NewArray(types.Typ[types.String], 4), wantType: "[String]"},
+		{name: "NamedArray", goType: newNamed("Checksum", types.NewArray(types.Typ[types.Uint8], 16)), wantType: "[Int]"},
+		{name: "ArrayOfNamedBasic", goType: types.NewArray(newNamed("Email", types.Typ[types.String]), 2), wantType: "[Email]", wantScalar: true},
+		{name: "NamedMap", goType: newNamed("Attrs", types.NewMap(types.Typ[types.String], types.Typ[types.String])), wantType: "attrsMap", wantNested: 1},
+		{name: "NamedPointer", goType: newNamed("Ref", types.NewPointer(types.Typ[types.Bool])), wantType: "Boolean"},
+		{name: "NamedBasicAsScalar", goType: newNamed("Email", types.Typ[types.String]), wantType: "Email", wantScalar: true},
+		{name: "NamedBasicUnwrapped", opts: ConvertOptions{UnwrapNamedBasic: true}, goType: newNamed("Email", types.Typ[types.String]), wantType: "String"},
+		{name: "SliceOfNamedBasic", goType: types.NewSlice(newNamed("Email", types.Typ[types.String])), wantType: "[Email]", wantScalar: true},
+		{
+			name:       "SliceOfNamedBasicInPackage",
+			goType:     types.NewSlice(types.NewNamed(types.NewTypeName(token.NoPos, models, "Status", nil), types.Typ[types.String], nil)),
+			wantType:   "[Status]",
+			wantScalar: true,
+			wantGoType: "github.com/acme/models.Status",
+		},
+		{name: "PointerToNamedMap", goType: types.NewPointer(newNamed("Attrs", types.NewMap(types.Typ[types.String], types.Typ[types.Int]))), wantType: "attrsMap", wantNested: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GqlFieldsDefinition{GqlFieldName: "attrs"}
+			err := newConverter(&tt.opts).convertType(tt.goType, &got)
+			if err != nil {
+				t.Fatalf("convertType() error = %v", err)
+			}
+			if got.GqlFieldType != tt.wantType || got.IsCustomScalar != tt.wantScalar || len(got.NestedCustomType) != tt.wantNested {
+				t.Errorf("convertType() = %s (scalar %v, nested %d), want %s (scalar %v, nested %d)",
+					got.GqlFieldType, got.IsCustomScalar, len(got.NestedCustomType), tt.wantType, tt.wantScalar, tt.wantNested)
+			}
+			if got.GqlFieldGoType != tt.wantGoType {
+				t.Errorf("convertType() Go type = %q, want %q", got.GqlFieldGoType, tt.wantGoType)
+			}
+		})
+	}
+}
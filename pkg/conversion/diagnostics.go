@@ -0,0 +1,129 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// Severity represents how serious a Diagnostic is.
+type Severity string
+
+// Severities of a Diagnostic
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Codes identifying the kind of issue a Diagnostic reports
+const (
+	CodeInvalidType     = "invalid-type"     // CodeInvalidType reports a field whose type cannot be converted
+	CodeUnsupportedType = "unsupported-type" // CodeUnsupportedType reports a field of an unsupported type that was skipped or converted into a scalar
+	CodeMarshaler       = "marshaler"        // CodeMarshaler reports a field whose type was decided by a marshaler
+	CodeInvalidName     = "invalid-name"     // CodeInvalidName reports a type, scalar or field whose name is not a valid GraphQL name
+	CodeEmptyType       = "empty-type"       // CodeEmptyType reports a type without any field
+	CodeUndefinedType   = "undefined-type"   // CodeUndefinedType reports a field whose type is not defined in the schema
+	CodeDuplicateName   = "duplicate-name"   // CodeDuplicateName reports a type, scalar or field defined more than once
+	CodeTypePosition    = "type-position"    // CodeTypePosition reports an output type used by an input field, or the opposite
+	CodeGqlgenBinding   = "gqlgen-binding"   // CodeGqlgenBinding reports a field gqlgen would not bind to its Go struct field
+	CodeRelation        = "relation"         // CodeRelation reports a relation inferred from a foreign key field
+	CodeFederation      = "federation"       // CodeFederation reports an entity not meeting the requirements of Apollo Federation
+	CodeDirective       = "directive"        // CodeDirective reports a directive not defined, or not used according to its definition
+)
+
+// Diagnostic represents an issue found while converting Go structs into GraphQL types.
+type Diagnostic struct {
+	Severity   Severity       // Severity is how serious the issue is
+	Code       string         // Code is a machine-readable identifier of the kind of issue, e.g. CodeInvalidType
+	Position   token.Position // Position is the position in the Go source the issue relates to, if known
+	StructName string         // StructName is the name of the struct the issue relates to, if any
+	FieldName  string         // FieldName is the name of the field the issue relates to, if any
+	Message    string         // Message describes the issue
+}
+
+// String returns the Diagnostic as "file:line:col: severity: Struct.Field: message [code]".
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	if d.Position.IsValid() {
+		b.WriteString(d.Position.String() + ": ")
+	}
+	b.WriteString(string(d.Severity) + ": ")
+	if d.StructName != "" {
+		b.WriteString(d.StructName)
+		if d.FieldName != "" {
+			b.WriteString("." + d.FieldName)
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(d.Message)
+	if d.Code != "" {
+		b.WriteString(fmt.Sprintf(" [%s]", d.Code))
+	}
+	return b.String()
+}
+
+// MarshalJSON encodes the Diagnostic as a flat JSON object.
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Severity Severity `json:"severity"`
+		Code     string   `json:"code,omitempty"`
+		File     string   `json:"file,omitempty"`
+		Line     int      `json:"line,omitempty"`
+		Column   int      `json:"column,omitempty"`
+		Struct   string   `json:"struct,omitempty"`
+		Field    string   `json:"field,omitempty"`
+		Message  string   `json:"message"`
+	}{d.Severity, d.Code, d.Position.Filename, d.Position.Line, d.Position.Column, d.StructName, d.FieldName, d.Message})
+}
+
+// Diagnostics is a list of Diagnostic. It is also an error reporting its Diagnostic, one per line.
+type Diagnostics []Diagnostic
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, len(d))
+	for i, diagnostic := range d {
+		lines[i] = diagnostic.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HasErrors returns true if any Diagnostic has SeverityError.
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the Diagnostic with SeverityError as an error, or nil if there is none.
+func (d Diagnostics) Err() error {
+	var errs Diagnostics
+	for _, diagnostic := range d {
+		if diagnostic.Severity == SeverityError {
+			errs = append(errs, diagnostic)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// diagnose records a Diagnostic for the field being converted.
+func (c *converter) diagnose(severity Severity, code string, message string) {
+	diagnostic := Diagnostic{
+		Severity:   severity,
+		Code:       code,
+		StructName: c.context.structName,
+		FieldName:  c.context.fieldName,
+		Message:    message,
+	}
+	if c.fset != nil && c.context.pos.IsValid() {
+		diagnostic.Position = c.fset.Position(c.context.pos)
+	}
+	c.diagnostics = append(c.diagnostics, diagnostic)
+}
@@ -0,0 +1,83 @@
+package conversion
+
+import (
+	"encoding/json"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/load"
+)
+
+// TestDiagnosticString is a unit test for the text and JSON representations of a Diagnostic.
+func TestDiagnosticString(t *testing.T) {
+	tests := []struct {
+		name       string
+		diagnostic Diagnostic
+		wantText   string
+		wantJson   string
+	}{
+		{
+			name: "WithPosition",
+			diagnostic: Diagnostic{
+				Severity:   SeverityError,
+				Code:       CodeInvalidType,
+				Position:   token.Position{Filename: "models.go", Line: 12, Column: 2},
+				StructName: "Article",
+				FieldName:  "Events",
+				Message:    "invalid type: chan bool",
+			},
+			wantText: "models.go:12:2: error: Article.Events: invalid type: chan bool [invalid-type]",
+			wantJson: `{"severity":"error","code":"invalid-type","file":"models.go","line":12,"column":2,"struct":"Article","field":"Events","message":"invalid type: chan bool"}`,
+		},
+		{
+			name:       "WithoutPosition",
+			diagnostic: Diagnostic{Severity: SeverityWarning, Message: "something odd"},
+			wantText:   "warning: something odd",
+			wantJson:   `{"severity":"warning","message":"something odd"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diagnostic.String(); got != tt.wantText {
+				t.Errorf("String() = %v, want %v", got, tt.wantText)
+			}
+			gotJson, err := json.Marshal(tt.diagnostic)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(gotJson) != tt.wantJson {
+				t.Errorf("MarshalJSON() = %s, want %s", gotJson, tt.wantJson)
+			}
+		})
+	}
+}
+
+// TestDiagnosticsCollected checks that the Diagnostics of all the structs are returned together.
+func TestDiagnosticsCollected(t *testing.T) {
+	pkg := types.NewPackage("some.pkg/path", "path")
+	newStruct := func(name string, fieldType types.Type) load.StructDiscovered {
+		field := types.NewVar(token.NoPos, pkg, "Field", fieldType)
+		return load.StructDiscovered{
+			Name: types.NewTypeName(token.NoPos, pkg, name, nil),
+			Obj:  types.NewStruct([]*types.Var{field}, nil),
+		}
+	}
+	structsFound := []load.StructDiscovered{
+		newStruct("First", types.NewChan(types.SendRecv, types.Typ[types.Bool])),
+		newStruct("Second", types.Typ[types.String]),
+		newStruct("Third", types.NewSignatureType(nil, nil, nil, nil, nil, false)),
+	}
+
+	gqlTypeDefs, diagnostics, err := BuildGqlTypesWithOptions(structsFound, &ConvertOptions{})
+	if err == nil || gqlTypeDefs != nil {
+		t.Fatalf("BuildGqlTypesWithOptions() = %v, %v, want an error", gqlTypeDefs, err)
+	}
+	if len(diagnostics) != 2 || diagnostics[0].StructName != "First" || diagnostics[1].StructName != "Third" {
+		t.Errorf("BuildGqlTypesWithOptions() diagnostics = %v", diagnostics)
+	}
+	if !diagnostics.HasErrors() {
+		t.Errorf("HasErrors() = false, want true")
+	}
+}
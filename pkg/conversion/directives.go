@@ -0,0 +1,484 @@
+package conversion
+
+import (
+	"fmt"
+	"github.com/fatih/structtag"
+	"strconv"
+	"strings"
+)
+
+// DirectiveTag is the key of the struct tag applying directives to a field, e.g. `gqldirective:"@auth(requires: ADMIN)"`.
+const DirectiveTag = "gqldirective"
+
+// DirectiveDocDirective is the doc directive applying directives to the type of a struct, e.g.
+// //gql:directive @auth(requires: ADMIN).
+const DirectiveDocDirective = "directive"
+
+// Directive locations of the types and fields of a schema, see https://spec.graphql.org/October2021/#DirectiveLocations
+const (
+	LocationObject               = "OBJECT"
+	LocationInputObject          = "INPUT_OBJECT"
+	LocationFieldDefinition      = "FIELD_DEFINITION"
+	LocationInputFieldDefinition = "INPUT_FIELD_DEFINITION"
+)
+
+// directiveLocations are the valid directive locations.
+var directiveLocations = map[string]bool{
+	"QUERY": true, "MUTATION": true, "SUBSCRIPTION": true, "FIELD": true, "FRAGMENT_DEFINITION": true,
+	"FRAGMENT_SPREAD": true, "INLINE_FRAGMENT": true, "VARIABLE_DEFINITION": true, "SCHEMA": true, "SCALAR": true,
+	LocationObject: true, LocationFieldDefinition: true, "ARGUMENT_DEFINITION": true, "INTERFACE": true, "UNION": true,
+	"ENUM": true, "ENUM_VALUE": true, LocationInputObject: true, LocationInputFieldDefinition: true,
+}
+
+// builtinDirectives are the directives defined by the GraphQL specification, which need no definition.
+var builtinDirectives = map[string]bool{"deprecated": true, "specifiedBy": true, "include": true, "skip": true}
+
+// directiveDefinition represents a parsed directive definition.
+type directiveDefinition struct {
+	name          string
+	argumentNames []string        // argumentNames are the names of the arguments, in the order they are defined
+	arguments     map[string]bool // arguments are the arguments of the directive, true for the required ones
+	// argumentTypes are the named types of the arguments, e.g. Role for requires: [Role!]!, by name
+	argumentTypes map[string]string
+	repeatable    bool
+	locations     map[string]bool
+}
+
+// directiveParser parses the directives and directive definitions written in the GraphQL syntax.
+type directiveParser struct {
+	src    string
+	tokens []string
+	idx    int
+}
+
+// newDirectiveParser returns a directiveParser of src, or an error if src cannot be tokenized.
+func newDirectiveParser(src string) (*directiveParser, error) {
+	p := &directiveParser{src: src}
+	for idx := 0; idx < len(src); {
+		c := src[idx]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			idx++
+		case strings.IndexByte("!$()[]{}:=@|", c) >= 0:
+			p.tokens = append(p.tokens, string(c))
+			idx++
+		case c == '"':
+			end := idx + 1
+			for end < len(src) && src[end] != '"' {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string in %q", src)
+			}
+			p.tokens = append(p.tokens, src[idx:end+1])
+			idx = end + 1
+		case c == '_' || c == '-' || isAlphaNum(c):
+			end := idx + 1
+			for end < len(src) && (isAlphaNum(src[end]) || strings.IndexByte("_.+-", src[end]) >= 0) {
+				end++
+			}
+			p.tokens = append(p.tokens, src[idx:end])
+			idx = end
+		default:
+			return nil, fmt.Errorf("unexpected character %q in %q", c, src)
+		}
+	}
+	return p, nil
+}
+
+// isAlphaNum returns true if c is an ASCII letter or digit.
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// peek returns the current token, or an empty string at the end of the source.
+func (p *directiveParser) peek() string {
+	if p.idx == len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.idx]
+}
+
+// expect consumes the current token if it is token, or returns an error.
+func (p *directiveParser) expect(token string) error {
+	if p.peek() != token {
+		return p.errorf("expected %q", token)
+	}
+	p.idx++
+	return nil
+}
+
+// name consumes the current token if it is a valid GraphQL name, or returns an error.
+func (p *directiveParser) name() (string, error) {
+	name := p.peek()
+	if !gqlNameRegexp.MatchString(name) {
+		return "", p.errorf("expected a name")
+	}
+	p.idx++
+	return name, nil
+}
+
+// errorf returns an error locating the current token.
+func (p *directiveParser) errorf(format string, args ...interface{}) error {
+	found := "the end"
+	if p.idx < len(p.tokens) {
+		found = fmt.Sprintf("%q", p.tokens[p.idx])
+	}
+	return fmt.Errorf("%s, found %s in %q", fmt.Sprintf(format, args...), found, p.src)
+}
+
+// directives parses the directives up to the end of the source, e.g. @auth(requires: ADMIN) @cost(weight: 5).
+func (p *directiveParser) directives() ([]GqlSchemaDirective, error) {
+	var directives []GqlSchemaDirective
+	for p.peek() != "" {
+		if err := p.expect("@"); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		directive := GqlSchemaDirective{Name: name}
+		if p.peek() == "(" {
+			p.idx++
+			for p.peek() != ")" {
+				argName, err := p.name()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				value, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				directive.Arguments = append(directive.Arguments, GqlSchemaDirectiveArg{Name: argName, Value: value})
+			}
+			p.idx++
+			if len(directive.Arguments) == 0 {
+				return nil, p.errorf("expected the arguments of @%s", name)
+			}
+		}
+		directives = append(directives, directive)
+	}
+	return directives, nil
+}
+
+// value parses a constant value and returns it as printed, e.g. [ADMIN, USER] or {weight: 5}.
+func (p *directiveParser) value() (string, error) {
+	token := p.peek()
+	switch {
+	case token == "[":
+		p.idx++
+		var values []string
+		for p.peek() != "]" {
+			value, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			values = append(values, value)
+		}
+		p.idx++
+		return "[" + strings.Join(values, ", ") + "]", nil
+	case token == "{":
+		p.idx++
+		var fields []string
+		for p.peek() != "}" {
+			name, err := p.name()
+			if err != nil {
+				return "", err
+			}
+			if err := p.expect(":"); err != nil {
+				return "", err
+			}
+			value, err := p.value()
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, name+": "+value)
+		}
+		p.idx++
+		return "{" + strings.Join(fields, ", ") + "}", nil
+	case strings.HasPrefix(token, `"`), gqlNameRegexp.MatchString(token), isNumber(token):
+		p.idx++
+		return token, nil
+	}
+	return "", p.errorf("expected a constant value")
+}
+
+// isNumber returns true if token is a GraphQL integer or float value.
+func isNumber(token string) bool {
+	digits := strings.TrimPrefix(token, "-")
+	return digits != "" && digits[0] >= '0' && digits[0] <= '9'
+}
+
+// definition parses a directive definition, e.g. directive @auth(requires: Role!) on OBJECT | FIELD_DEFINITION.
+func (p *directiveParser) definition() (directiveDefinition, error) {
+	definition := directiveDefinition{arguments: make(map[string]bool), argumentTypes: make(map[string]string), locations: make(map[string]bool)}
+	if strings.HasPrefix(p.peek(), `"`) {
+		p.idx++
+	}
+	if err := p.expect("directive"); err != nil {
+		return definition, err
+	}
+	if err := p.expect("@"); err != nil {
+		return definition, err
+	}
+	var err error
+	if definition.name, err = p.name(); err != nil {
+		return definition, err
+	}
+	if p.peek() == "(" {
+		p.idx++
+		for p.peek() != ")" {
+			if strings.HasPrefix(p.peek(), `"`) {
+				p.idx++
+			}
+			argName, err := p.name()
+			if err != nil {
+				return definition, err
+			}
+			if err := p.expect(":"); err != nil {
+				return definition, err
+			}
+			namedType, nonNull, err := p.typeRef()
+			if err != nil {
+				return definition, err
+			}
+			hasDefault := p.peek() == "="
+			if hasDefault {
+				p.idx++
+				if _, err := p.value(); err != nil {
+					return definition, err
+				}
+			}
+			definition.argumentNames = append(definition.argumentNames, argName)
+			definition.arguments[argName] = nonNull && !hasDefault
+			definition.argumentTypes[argName] = namedType
+		}
+		p.idx++
+	}
+	if p.peek() == "repeatable" {
+		definition.repeatable = true
+		p.idx++
+	}
+	if err := p.expect("on"); err != nil {
+		return definition, err
+	}
+	if p.peek() == "|" {
+		p.idx++
+	}
+	for {
+		location := p.peek()
+		if !directiveLocations[location] {
+			return definition, p.errorf("expected a directive location")
+		}
+		definition.locations[location] = true
+		p.idx++
+		if p.peek() != "|" {
+			break
+		}
+		p.idx++
+	}
+	if p.peek() != "" {
+		return definition, p.errorf("expected the end of the definition")
+	}
+	return definition, nil
+}
+
+// typeRef parses a type reference, e.g. [Role!]!, and returns the named type it references, e.g. Role, and true if
+// it is non-null.
+func (p *directiveParser) typeRef() (string, bool, error) {
+	var namedType string
+	var err error
+	if p.peek() == "[" {
+		p.idx++
+		if namedType, _, err = p.typeRef(); err != nil {
+			return "", false, err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", false, err
+		}
+	} else if namedType, err = p.name(); err != nil {
+		return "", false, err
+	}
+	if p.peek() == "!" {
+		p.idx++
+		return namedType, true, nil
+	}
+	return namedType, false, nil
+}
+
+// parseDirectives parses directives written in the GraphQL syntax, e.g. @auth(requires: ADMIN) @cost(weight: 5).
+// The arguments are constant values, variables are not allowed.
+func parseDirectives(src string) ([]GqlSchemaDirective, error) {
+	p, err := newDirectiveParser(src)
+	if err != nil {
+		return nil, err
+	}
+	directives, err := p.directives()
+	if err != nil {
+		return nil, err
+	}
+	if len(directives) == 0 {
+		return nil, fmt.Errorf("no directive in %q", src)
+	}
+	return directives, nil
+}
+
+// typeDirectives returns the directives of the DirectiveDocDirective doc directives of the struct of the type typeName.
+func typeDirectives(typeName string, docDirectives []string) ([]GqlSchemaDirective, error) {
+	var directives []GqlSchemaDirective
+	for _, docDirective := range docDirectives {
+		name, src, _ := strings.Cut(docDirective, " ")
+		if name != DirectiveDocDirective {
+			continue
+		}
+		parsed, err := parseDirectives(src)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s%s doc directive of type %s: %w", DocDirectivePrefix, DirectiveDocDirective, typeName, err)
+		}
+		directives = append(directives, parsed...)
+	}
+	return directives, nil
+}
+
+// fieldDirectives returns the directives of the DirectiveTag of the field fieldName of the type typeName, if any.
+func fieldDirectives(tags *structtag.Tags, typeName string, fieldName string) ([]GqlSchemaDirective, error) {
+	tag, err := tags.Get(DirectiveTag)
+	if err != nil {
+		return nil, nil
+	}
+	directives, err := parseDirectives(tag.Value())
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s tag of field %s.%s: %w", DirectiveTag, typeName, fieldName, err)
+	}
+	return directives, nil
+}
+
+// parseDirectiveDefinition parses a directive definition written in the GraphQL syntax.
+func parseDirectiveDefinition(src string) (directiveDefinition, error) {
+	p, err := newDirectiveParser(src)
+	if err != nil {
+		return directiveDefinition{}, err
+	}
+	return p.definition()
+}
+
+// directiveDefinitions parses the directive definitions and returns them as GqlSchemaDirectiveDefinition, in the order
+// they are written. It returns an InvalidOptionErr error if a definition is not valid, or if two share a name.
+func directiveDefinitions(definitions []string) ([]GqlSchemaDirectiveDefinition, error) {
+	var schemaDefinitions []GqlSchemaDirectiveDefinition
+	names := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		parsed, err := parseDirectiveDefinition(definition)
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid directive definition: %v", InvalidOptionErr, err)
+		}
+		if names[parsed.name] {
+			return nil, fmt.Errorf("%v: directive @%s is defined more than once", InvalidOptionErr, parsed.name)
+		}
+		names[parsed.name] = true
+		schemaDefinitions = append(schemaDefinitions, GqlSchemaDirectiveDefinition{Name: parsed.name, Definition: strings.TrimSpace(definition)})
+	}
+	return schemaDefinitions, nil
+}
+
+// addDirectiveDefinitions adds the definitions of the directives the types and fields use to the schema, unless
+// already added.
+func (r *schemaResolver) addDirectiveDefinitions(definitions []GqlSchemaDirectiveDefinition) {
+	used := make(map[string]bool)
+	for _, schemaType := range r.types {
+		for _, directive := range schemaType.Directives {
+			used[directive.Name] = true
+		}
+		for _, field := range schemaType.Fields {
+			for _, directive := range field.Directives {
+				used[directive.Name] = true
+			}
+		}
+	}
+	for _, definition := range r.directives {
+		used[definition.Name] = false
+	}
+	for _, definition := range definitions {
+		if used[definition.Name] {
+			r.directives = append(r.directives, definition)
+		}
+	}
+}
+
+// linkedDirectives returns the names of the directives imported by the @link directives of the schema, e.g. key for
+// @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key"]).
+func linkedDirectives(schemaDirectives []GqlSchemaDirective) map[string]bool {
+	linked := make(map[string]bool)
+	for _, directive := range schemaDirectives {
+		if directive.Name != LinkDirective {
+			continue
+		}
+		for _, arg := range directive.Arguments {
+			if arg.Name != "import" {
+				continue
+			}
+			for _, imported := range strings.Fields(strings.Trim(arg.Value, "[]")) {
+				if name, err := strconv.Unquote(strings.TrimSuffix(imported, ",")); err == nil {
+					linked[strings.TrimPrefix(name, "@")] = true
+				}
+			}
+		}
+	}
+	return linked
+}
+
+// validateDirectives validates the directives applied to the type and its fields against their definitions: each
+// directive is defined, allowed on a type or field of its kind, given the arguments it defines and its required ones,
+// and not repeated unless repeatable. The built-in directives, and the directives imported by the @link of the schema,
+// e.g. the ones of Apollo Federation, need no definition.
+func (v *schemaValidator) validateDirectives(schemaType GqlSchemaType) {
+	typeLocation, fieldLocation := LocationObject, LocationFieldDefinition
+	if schemaType.IsInput() {
+		typeLocation, fieldLocation = LocationInputObject, LocationInputFieldDefinition
+	}
+	v.validateDirectivesAt(schemaType, "", schemaType.Directives, typeLocation)
+	for _, field := range schemaType.Fields {
+		v.validateDirectivesAt(schemaType, field.Name, field.Directives, fieldLocation)
+	}
+}
+
+// validateDirectivesAt validates the directives applied at the location to the field of the type, or to the type if
+// the field is empty.
+func (v *schemaValidator) validateDirectivesAt(schemaType GqlSchemaType, field string, directives []GqlSchemaDirective, location string) {
+	seen := make(map[string]bool)
+	for _, directive := range directives {
+		definition, ok := v.directives[directive.Name]
+		if !ok {
+			if !builtinDirectives[directive.Name] && !v.linked[directive.Name] {
+				v.report(schemaType, field, CodeDirective, fmt.Sprintf("directive @%s is not defined", directive.Name))
+			}
+			continue
+		}
+		if !definition.locations[location] {
+			v.report(schemaType, field, CodeDirective, fmt.Sprintf("directive @%s is not allowed on %s", directive.Name, location))
+		}
+		if seen[directive.Name] && !definition.repeatable {
+			v.report(schemaType, field, CodeDirective, fmt.Sprintf("directive @%s is not repeatable", directive.Name))
+		}
+		seen[directive.Name] = true
+		given := make(map[string]bool, len(directive.Arguments))
+		for _, arg := range directive.Arguments {
+			given[arg.Name] = true
+			if _, ok := definition.arguments[arg.Name]; !ok {
+				v.report(schemaType, field, CodeDirective, fmt.Sprintf("directive @%s has no argument %s", directive.Name, arg.Name))
+			}
+		}
+		for _, name := range definition.argumentNames {
+			if definition.arguments[name] && !given[name] {
+				v.report(schemaType, field, CodeDirective, fmt.Sprintf("directive @%s requires the argument %s", directive.Name, name))
+			}
+		}
+	}
+}
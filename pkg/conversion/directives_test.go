@@ -0,0 +1,201 @@
+package conversion
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseDirectives is a unit test for the parseDirectives function.
+func TestParseDirectives(t *testing.T) {
+	tests := []struct {
+		src     string
+		want    string
+		wantErr string
+	}{
+		{src: "@auth(requires: ADMIN)", want: "@auth(requires: ADMIN)"},
+		{src: "@cost(weight: 5,multipliers:[\"a\" \"b\"]) @public", want: `@cost(weight: 5, multipliers: ["a", "b"]) @public`},
+		{src: "@range(min: -1.5e3, max: {value: 10, strict: true})", want: "@range(min: -1.5e3, max: {value: 10, strict: true})"},
+		{src: `@doc(text: "a \"quoted\" (text)")`, want: `@doc(text: "a \"quoted\" (text)")`},
+		{src: "", wantErr: "no directive"},
+		{src: "auth", wantErr: `expected "@", found "auth"`},
+		{src: "@auth()", wantErr: "expected the arguments of @auth"},
+		{src: "@auth(requires ADMIN)", wantErr: `expected ":", found "ADMIN"`},
+		{src: "@auth(requires: $role)", wantErr: `expected a constant value, found "$"`},
+		{src: "@auth(requires: ADMIN", wantErr: "expected a name, found the end"},
+		{src: `@doc(text: "unterminated)`, wantErr: "unterminated string"},
+		{src: "@auth(requires: ADMIN);", wantErr: "unexpected character ';'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			directives, err := parseDirectives(tt.src)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseDirectives() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDirectives() error = %v", err)
+			}
+			if got := strings.TrimSpace(directivesString(directives)); got != tt.want {
+				t.Errorf("parseDirectives() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseDirectiveDefinition is a unit test for the parseDirectiveDefinition function.
+func TestParseDirectiveDefinition(t *testing.T) {
+	definition, err := parseDirectiveDefinition(`"Restricts the access" directive @auth(requires: Role! = ADMIN, "The scopes" scopes: [String!]!, reason: String) repeatable on | OBJECT | FIELD_DEFINITION`)
+	if err != nil {
+		t.Fatalf("parseDirectiveDefinition() error = %v", err)
+	}
+	if definition.name != "auth" || !definition.repeatable || len(definition.locations) != 2 || !definition.locations[LocationObject] {
+		t.Errorf("parseDirectiveDefinition() = %+v", definition)
+	}
+	// A non-null argument with a default value may be left out
+	if got, want := strings.Join(definition.argumentNames, " "), "requires scopes reason"; got != want {
+		t.Errorf("parseDirectiveDefinition() arguments = %q, want %q", got, want)
+	}
+	if definition.arguments["requires"] || !definition.arguments["scopes"] || definition.arguments["reason"] {
+		t.Errorf("parseDirectiveDefinition() required arguments = %v", definition.arguments)
+	}
+
+	for _, src := range []string{
+		"@auth on OBJECT",
+		"directive @auth",
+		"directive @auth on OBJECTS",
+		"directive @auth(requires: [Role!) on OBJECT",
+		"directive @auth on OBJECT FIELD_DEFINITION",
+	} {
+		if _, err := parseDirectiveDefinition(src); err == nil {
+			t.Errorf("parseDirectiveDefinition(%q) error = nil, want an error", src)
+		}
+	}
+}
+
+// TestCustomDirectives is a unit test for the custom directives of the tags and doc comments of ResolveGqlSchema.
+func TestCustomDirectives(t *testing.T) {
+	auth := "directive @auth(requires: Role!) on OBJECT | FIELD_DEFINITION"
+	cost := "directive @cost(weight: Int!) on FIELD_DEFINITION"
+	tests := []struct {
+		name        string
+		directives  []string
+		definitions []string
+		tags        string
+		want        string
+		wantErr     string
+	}{
+		{
+			name:        "Directives",
+			directives:  []string{EntityDocDirective, "directive @auth(requires: ADMIN)"},
+			definitions: []string{cost, auth, "directive @unused on OBJECT"},
+			tags:        `json:"title" gqldirective:"@auth(requires: USER) @cost(weight: 5)"`,
+			want: cost + "\n" + auth + "\n\n" +
+				"type Post @auth(requires: ADMIN) {\n  title: String @auth(requires: USER) @cost(weight: 5)\n}\n\n",
+		},
+		{
+			// The definitions are only added when a directive uses them
+			name:        "NoDirective",
+			definitions: []string{auth},
+			tags:        `json:"title"`,
+			want:        "\ntype Post {\n  title: String\n}\n\n",
+		},
+		{
+			name:    "InvalidTag",
+			tags:    `json:"title" gqldirective:"auth"`,
+			wantErr: `invalid gqldirective tag of field Post.title: expected "@"`,
+		},
+		{
+			name:       "InvalidDocDirective",
+			directives: []string{"directive @auth(requires:)"},
+			tags:       `json:"title"`,
+			wantErr:    "invalid //gql:directive doc directive of type Post: expected a constant value",
+		},
+		{
+			name:        "InvalidDefinition",
+			definitions: []string{"directive @auth on OBJECTS"},
+			wantErr:     "invalid directive definition",
+		},
+		{
+			name:        "DuplicateDefinition",
+			definitions: []string{auth, "directive @auth on OBJECT"},
+			wantErr:     "directive @auth is defined more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs := []GqlTypeDefinition{{GqlTypeName: "Post", GqlTypeDirectives: tt.directives, GqlFields: []GqlFieldsDefinition{
+				{GqlFieldName: "Title", GqlFieldType: "String", GqlFieldTags: tt.tags},
+			}}}
+			schema, err := ResolveGqlSchema(defs, &PrettyPrintOptions{UseJsonTags: true, DirectiveDefinitions: tt.definitions})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveGqlSchema() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveGqlSchema() error = %v", err)
+			}
+			if schema.String() != tt.want {
+				t.Errorf("ResolveGqlSchema() = %q, want %q", schema.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateDirectives is a unit test for the validation of the directives of ValidateGqlSchema.
+func TestValidateDirectives(t *testing.T) {
+	definitions := []GqlSchemaDirectiveDefinition{
+		{Name: "auth", Definition: "directive @auth(requires: Role!, reason: String) on OBJECT | FIELD_DEFINITION"},
+		{Name: "tag", Definition: "directive @tag(name: String!) repeatable on FIELD_DEFINITION | INPUT_FIELD_DEFINITION"},
+	}
+	auth := GqlSchemaDirective{Name: "auth", Arguments: []GqlSchemaDirectiveArg{{Name: "requires", Value: "ADMIN"}}}
+	tag := GqlSchemaDirective{Name: "tag", Arguments: []GqlSchemaDirectiveArg{{Name: "name", Value: `"a"`}}}
+	tests := []struct {
+		name             string
+		typeDirectives   []GqlSchemaDirective
+		fieldDirectives  []GqlSchemaDirective
+		input            bool
+		schemaDirectives []GqlSchemaDirective
+		want             string
+	}{
+		{name: "Valid", typeDirectives: []GqlSchemaDirective{auth}, fieldDirectives: []GqlSchemaDirective{auth, tag, tag, {Name: "deprecated"}}},
+		{name: "Undefined", fieldDirectives: []GqlSchemaDirective{{Name: "cost"}}, want: "User.name: directive @cost is not defined"},
+		{
+			name:             "Linked",
+			typeDirectives:   []GqlSchemaDirective{{Name: KeyDirective, Arguments: []GqlSchemaDirectiveArg{{Name: "fields", Value: `"name"`}}}},
+			schemaDirectives: []GqlSchemaDirective{{Name: LinkDirective, Arguments: []GqlSchemaDirectiveArg{{Name: "import", Value: `["@key", "@shareable"]`}}}},
+		},
+		{name: "Location", typeDirectives: []GqlSchemaDirective{tag}, want: "User: directive @tag is not allowed on OBJECT"},
+		{name: "InputLocation", input: true, fieldDirectives: []GqlSchemaDirective{auth}, want: "User.name: directive @auth is not allowed on INPUT_FIELD_DEFINITION"},
+		{name: "Repeated", typeDirectives: []GqlSchemaDirective{auth, auth}, want: "User: directive @auth is not repeatable"},
+		{name: "MissingArgument", fieldDirectives: []GqlSchemaDirective{{Name: "auth"}}, want: "User.name: directive @auth requires the argument requires"},
+		{
+			name:            "UnknownArgument",
+			fieldDirectives: []GqlSchemaDirective{{Name: "tag", Arguments: append(tag.Arguments, GqlSchemaDirectiveArg{Name: "color", Value: "RED"})}},
+			want:            "User.name: directive @tag has no argument color",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaType := GqlSchemaType{Name: "User", Directives: tt.typeDirectives, Fields: []GqlSchemaField{{Name: "name", Type: "String", Directives: tt.fieldDirectives}}}
+			if tt.input {
+				schemaType.Kind = KindInput
+			}
+			schema := &GqlSchema{SchemaDirectives: tt.schemaDirectives, Directives: definitions, Scalars: []GqlSchemaScalar{{Name: "Role"}}, Types: []GqlSchemaType{schemaType}}
+			diagnostics := ValidateGqlSchema(schema, false)
+			if tt.want == "" {
+				if len(diagnostics) != 0 {
+					t.Errorf("ValidateGqlSchema() = %v, want no diagnostic", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].String() != "warning: "+tt.want+" ["+CodeDirective+"]" {
+				t.Errorf("ValidateGqlSchema() = %v, want %q", diagnostics, tt.want)
+			}
+		})
+	}
+}
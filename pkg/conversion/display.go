@@ -4,17 +4,47 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/fatih/structtag"
+	"strings"
 )
 
 // PrettyPrintOptions represents the options for pretty-printing. It contains the following fields:
 // - UseJsonTags: a bool indicating whether to use JSON tags
 // - UseCustomTags: a string indicating the custom tags to use
 // - RequireTags: a SpecTagRequire struct that specifies required tags
+// - Order: a string selecting the order of the types and scalars, see OrderAlpha, OrderSource and OrderTopo.
+// When empty, OrderAlpha is used.
+// - SortFields: a bool indicating whether the fields of a type are sorted alphabetically rather than kept in declaration order
+// - EmptyPolicy: a string selecting how the types without any field are handled, see EmptyPolicySkip,
+// EmptyPolicyPlaceholder and EmptyPolicyScalar. When empty, they are kept as is, which is not valid GraphQL.
+// - GoModelDirectives: a bool indicating whether the gqlgen @goModel and @goField directives binding the types to the
+// Go structs are added, see GoModelDirective
+// - Connections: a string selecting the fields listing objects replaced by a Relay connection, see ConnectionsTagged
+// and ConnectionsAll. When empty, ConnectionsTagged is used.
+// - Operations: a bool indicating whether the Query and Mutation types are extended with the CRUD operations of the
+// structs marked with EntityDocDirective
+// - Relations: a string selecting whether the relations inferred from the foreign key fields are added next to them,
+// see RelationsAdd, or replace them, see RelationsReplace. When empty, no relation is inferred.
+// - Federation: a bool indicating whether the Apollo Federation directives are added, see KeyDirective, and the schema
+// linked to the FederationURL
+// - IDRules: an IDRules struct selecting the fields converted into the GraphQL ID type, besides the ones tagged
+// `gql:"type=ID"`. When empty, only the tagged fields are converted.
+// - DirectiveDefinitions: a slice of directive definitions, e.g. directive @auth(requires: Role!) on FIELD_DEFINITION,
+// added to the schema when the directives of the DirectiveTag and DirectiveDocDirective use them
 type PrettyPrintOptions struct {
-	UseJsonTags      bool
-	UseCustomTags    string
-	TagFieldToIgnore *string
-	RequireTags      SpecTagRequire
+	UseJsonTags          bool
+	UseCustomTags        string
+	TagFieldToIgnore     *string
+	RequireTags          SpecTagRequire
+	Order                string
+	SortFields           bool
+	EmptyPolicy          string
+	GoModelDirectives    bool
+	Connections          string
+	Operations           bool
+	Relations            string
+	Federation           bool
+	IDRules              IDRules
+	DirectiveDefinitions []string
 }
 
 // SpecTagRequire defines the structure for specifying required tags.
@@ -48,144 +78,159 @@ func (opts *PrettyPrintOptions) tagFieldsValueToIgnore() string {
 }
 
 // GqlPrettyPrint takes a slice of GqlTypeDefinition and PrettyPrintOptions and returns a string representation of the GraphQL type definitions.
+// It resolves the GqlSchema of the type definitions using ResolveGqlSchema and prints it.
 func GqlPrettyPrint(gqlTypeDefs []GqlTypeDefinition, opts *PrettyPrintOptions) (string, error) {
-	var gqlType bytes.Buffer
-
-	// Write the Scalar on top of the string
-	scalarOutput := gqlPrettyPrintScalar(gqlTypeDefs, nil)
-	if scalarOutput != "" {
-		gqlType.WriteString(scalarOutput)
-	}
-	gqlType.WriteString("\n")
-
-	// Write the Type Definition
-	gqlTypeDefinition, err := gqlPrettyPrintTypes(gqlTypeDefs, opts)
+	schema, err := ResolveGqlSchema(gqlTypeDefs, opts)
 	if err != nil {
 		return "", err
 	}
-	gqlType.WriteString(gqlTypeDefinition)
-	return gqlType.String(), nil
+	return schema.String(), nil
 }
 
-// gqlPrettyPrintScalar takes a slice of GqlTypeDefinition and a map of setScalar.
-// It returns a string representation of the GraphQL scalar type definitions.
-func gqlPrettyPrintScalar(gqlTypeDefs []GqlTypeDefinition, setScalar map[string]bool) string {
-	var gqlScalarType bytes.Buffer
+// String returns the GraphQL schema definition of the GqlSchema: the schema extension, the directive definitions and the
+// custom scalars on top, followed by the types.
+func (s *GqlSchema) String() string {
+	var gqlType bytes.Buffer
 
-	// Dealing with recursive Case
-	if setScalar == nil {
-		setScalar = make(map[string]bool)
+	if len(s.SchemaDirectives) != 0 {
+		gqlType.WriteString(SchemaExtensionString(s.SchemaDirectives))
+	}
+	if len(s.SchemaDirectives) != 0 && (len(s.Directives) != 0 || len(s.Scalars) != 0) {
+		gqlType.WriteString("\n")
 	}
 
-	for _, gqlTypeDef := range gqlTypeDefs {
-		for _, field := range gqlTypeDef.GqlFields {
-			if field.IsCustomScalar {
-				setScalar[field.GqlFieldType] = true
-			}
-			if len(field.NestedCustomType) != 0 {
-				_ = gqlPrettyPrintScalar(field.NestedCustomType, setScalar)
-			}
-		}
+	for _, directive := range s.Directives {
+		gqlType.WriteString(directive.String())
+	}
+	if len(s.Directives) != 0 && len(s.Scalars) != 0 {
+		gqlType.WriteString("\n")
 	}
 
-	for scalar := range setScalar {
-		gqlScalarType.WriteString(fmt.Sprintf("scalar %s\n", scalar))
+	// Write the Scalar on top of the string
+	for _, scalar := range s.Scalars {
+		gqlType.WriteString(scalar.String())
 	}
-	return gqlScalarType.String()
-}
+	gqlType.WriteString("\n")
 
-// gqlPrettyPrintTypes takes a slice of GqlTypeDefinition and PrettyPrintOptions and returns a string representation of the GraphQL type definitions.
-func gqlPrettyPrintTypes(gqlTypeDefs []GqlTypeDefinition, opts *PrettyPrintOptions) (string, error) {
-	var gqlType bytes.Buffer
-	anyTagToUse := opts.tagToUse()
-	tagValueToIgnore := opts.tagFieldsValueToIgnore()
+	// Write the Type Definition
+	for _, schemaType := range s.Types {
+		gqlType.WriteString(schemaType.String())
+		gqlType.WriteString("\n")
+	}
+	return gqlType.String()
+}
 
-	for _, gqlTypeDef := range gqlTypeDefs {
-		var nestedCustomToWrite string
-		gqlType.WriteString(fmt.Sprintf("type %s {\n", gqlTypeDef.GqlTypeName))
+// SchemaExtensionString returns the schema extension applying the directives to the schema, e.g.
+// extend schema @link(url: "https://specs.apollo.dev/federation/v2.3").
+func SchemaExtensionString(directives []GqlSchemaDirective) string {
+	return "extend schema" + directivesString(directives) + "\n"
+}
 
-		for _, field := range gqlTypeDef.GqlFields {
-			fieldDef, err := gqlCreateFieldDefinition(field, anyTagToUse, tagValueToIgnore, &opts.RequireTags)
-			if err != nil {
-				return "", err
-			}
-			gqlType.WriteString(fieldDef)
+// String returns the GraphQL directive definition of the GqlSchemaDirectiveDefinition.
+func (d GqlSchemaDirectiveDefinition) String() string {
+	return d.Definition + "\n"
+}
 
-			if len(field.NestedCustomType) != 0 {
-				nestedCustomToWrite, err = gqlPrettyPrintTypes(field.NestedCustomType, opts)
-				if err != nil {
-					return "", err
-				}
-			}
-		}
-		gqlType.WriteString("}\n\n")
-		if nestedCustomToWrite != "" {
-			gqlType.WriteString(nestedCustomToWrite)
-		}
-	}
-	return gqlType.String(), nil
+// String returns the GraphQL scalar type definition of the GqlSchemaScalar.
+func (s GqlSchemaScalar) String() string {
+	return fmt.Sprintf("scalar %s\n", s.Name)
 }
 
-// createEmbeddedFieldOutput takes a GqlFieldsDefinition, a tag string, a tagValueToIgnore string,
-// and a requiredTags pointer to SpecTagRequire, and returns a string representation of the
-// embedded fields' output.
-func createEmbeddedFieldOutput(field GqlFieldsDefinition, tag string, tagValueToIgnore string, requiredTags *SpecTagRequire) (string, error) {
-	var embeddedFieldOutput string
-	for _, embeddedField := range field.GqlGenFieldsEmbedded {
-		thisEmbeddedFieldOutput, err := gqlCreateFieldDefinition(embeddedField, tag, tagValueToIgnore, requiredTags)
-		if err != nil {
-			return "", err
-		}
-		embeddedFieldOutput += thisEmbeddedFieldOutput
+// String returns the GraphQL type definition of the GqlSchemaType.
+func (t GqlSchemaType) String() string {
+	var gqlType bytes.Buffer
+	if t.Extend {
+		gqlType.WriteString("extend ")
 	}
-	return embeddedFieldOutput, nil
+	gqlType.WriteString(fmt.Sprintf("%s %s%s {\n", kindKeyword(t), t.Name, directivesString(t.Directives)))
+	for _, field := range t.Fields {
+		gqlType.WriteString(field.String())
+	}
+	gqlType.WriteString("}\n")
+	return gqlType.String()
 }
 
-// createFieldOutput takes a GqlFieldsDefinition, a fieldName string, and a requiredFieldmark string
-// and returns a string representation of the GraphQL field output.
-func createFieldOutput(field GqlFieldsDefinition, fieldName string, requiredFieldmark string) string {
-	return fmt.Sprintf("  %s: %s%s\n", fieldName, field.GqlFieldType, requiredFieldmark)
+// String returns the GraphQL field definition of the GqlSchemaField.
+func (f GqlSchemaField) String() string {
+	requiredFieldmark := ""
+	if f.NonNull {
+		requiredFieldmark = "!"
+	}
+	return fmt.Sprintf("  %s%s: %s%s%s\n", f.Name, argumentsString(f.Arguments), f.Type, requiredFieldmark, directivesString(f.Directives))
 }
 
-// gqlCreateFieldDefinition takes a GqlFieldsDefinition, a tag string, and a SpecTagRequire
-// and returns a string representation of the GraphQL field definition.
-func gqlCreateFieldDefinition(field GqlFieldsDefinition, tag string, tagValueToIgnore string, requiredTags *SpecTagRequire) (string, error) {
-	var thisFieldOutput string
-	var embeddedFieldOutput string
-	var err error
-
-	if field.GqlFieldIsEmbedded {
-		embeddedFieldOutput, err = createEmbeddedFieldOutput(field, tag, tagValueToIgnore, requiredTags)
-		if err != nil {
-			return "", err
+// argumentsString returns the arguments definition of a field, e.g. (first: Int = 10, after: String), or an empty string
+// if the field has no argument.
+func argumentsString(arguments []GqlSchemaArgument) string {
+	if len(arguments) == 0 {
+		return ""
+	}
+	args := make([]string, len(arguments))
+	for i, arg := range arguments {
+		args[i] = fmt.Sprintf("%s: %s", arg.Name, arg.Type)
+		if arg.DefaultValue != "" {
+			args[i] += " = " + arg.DefaultValue
 		}
 	}
+	return "(" + strings.Join(args, ", ") + ")"
+}
 
-	tags, err := parseFieldTags(field)
-	if err != nil {
-		return "", err
+// String returns the GraphQL directive of the GqlSchemaDirective, e.g. @goField(name: "PublishedAt").
+func (d GqlSchemaDirective) String() string {
+	if len(d.Arguments) == 0 {
+		return "@" + d.Name
 	}
+	args := make([]string, len(d.Arguments))
+	for i, arg := range d.Arguments {
+		args[i] = fmt.Sprintf("%s: %s", arg.Name, arg.Value)
+	}
+	return fmt.Sprintf("@%s(%s)", d.Name, strings.Join(args, ", "))
+}
 
-	fieldName, err := updateFieldName(field.GqlFieldName, tags, tag)
-	if err != nil {
-		return "", err
+// directivesString returns the directives separated by spaces, preceded by a space if there is any.
+func directivesString(directives []GqlSchemaDirective) string {
+	var str strings.Builder
+	for _, directive := range directives {
+		str.WriteString(" ")
+		str.WriteString(directive.String())
 	}
+	return str.String()
+}
 
-	if fieldName == tagValueToIgnore {
-		return "", nil
+// namedGqlType returns the named type of a GraphQL type, i.e. without any list or non-null wrapper.
+func namedGqlType(gqlType string) string {
+	return strings.Trim(gqlType, "[]!")
+}
+
+// fieldOutputType returns the GraphQL type to output for a field.
+// A basic field tagged with `json:",string"` is encoded as a JSON string, so it is output as a String.
+func fieldOutputType(field GqlFieldsDefinition) string {
+	if isJsonStringField(field) {
+		return "String"
 	}
+	return field.GqlFieldType
+}
 
-	requiredFieldmark, err := updateRequiredFieldMark(tags, requiredTags, "")
+// isJsonStringField returns true if the field is a basic kind with the `json:",string"` option,
+// as encoding/json then quotes its value.
+func isJsonStringField(field GqlFieldsDefinition) bool {
+	if !field.IsBasicKind {
+		return false
+	}
+	tags, err := parseFieldTags(field)
 	if err != nil {
-		return "", err
+		return false
 	}
+	return hasJsonTagOption(tags, "string")
+}
 
-	if !field.GqlFieldIsEmbedded {
-		thisFieldOutput = createFieldOutput(field, fieldName, requiredFieldmark)
+// hasJsonTagOption returns true if the json tag of a field has the given option, e.g. omitempty.
+func hasJsonTagOption(tags *structtag.Tags, option string) bool {
+	jsonTag, err := tags.Get("json")
+	if err != nil {
+		return false
 	}
-
-	output := embeddedFieldOutput + thisFieldOutput
-	return output, nil
+	return jsonTag.HasOption(option)
 }
 
 // parseFieldTags takes a GqlFieldsDefinition and returns the parsed struct tags using structtag.Parse.
@@ -205,15 +250,20 @@ func updateFieldName(fieldName string, tags *structtag.Tags, tag string) (string
 			if err.Error() != "tag does not exist" {
 				return "", err
 			}
-		} else {
+		} else if specifiedTag.Name != "" {
+			// e.g. `json:",omitempty"` keeps the field name
 			fieldName = specifiedTag.Name
 		}
 	}
 	return fieldName, nil
 }
 
-// updateRequiredFieldMark appends the fields "!" if the field has a tag that was marked as required
+// updateRequiredFieldMark appends the fields "!" if the field has a tag that was marked as required.
+// A field with the `json:",omitempty"` option may be absent, so it is always left nullable.
 func updateRequiredFieldMark(tags *structtag.Tags, requiredTags *SpecTagRequire, requiredFieldmark string) (string, error) {
+	if hasJsonTagOption(tags, "omitempty") {
+		return "", nil
+	}
 	if requiredTags.Key != "" && requiredTags.Val != "" {
 		tagValue, err := tags.Get(requiredTags.Key)
 		if err != nil {
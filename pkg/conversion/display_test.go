@@ -21,6 +21,29 @@ func TestGqlPrettyPrint(t *testing.T) {
 			want:    "\n",
 			wantErr: false,
 		},
+		{
+			name: "JSON string option",
+			input: []GqlTypeDefinition{
+				{GqlTypeName: "Counter", GqlFields: []GqlFieldsDefinition{
+					{GqlFieldName: "Total", GqlFieldType: "BigInt", GqlFieldTags: `json:"total,string"`, IsCustomScalar: true, IsBasicKind: true},
+				}},
+			},
+			opts:    &PrettyPrintOptions{UseJsonTags: true},
+			want:    "\ntype Counter {\n  total: String\n}\n\n",
+			wantErr: false,
+		},
+		{
+			name: "JSON omitempty option",
+			input: []GqlTypeDefinition{
+				{GqlTypeName: "User", GqlFields: []GqlFieldsDefinition{
+					{GqlFieldName: "ID", GqlFieldType: "Int", GqlFieldTags: `json:"id" validate:"required"`},
+					{GqlFieldName: "Email", GqlFieldType: "String", GqlFieldTags: `json:",omitempty" validate:"required"`},
+				}},
+			},
+			opts:    &PrettyPrintOptions{UseJsonTags: true, RequireTags: SpecTagRequire{Key: "validate", Val: "required"}},
+			want:    "\ntype User {\n  id: Int!\n  Email: String\n}\n\n",
+			wantErr: false,
+		},
 		// Will add more real test cases here
 	}
 
@@ -0,0 +1,94 @@
+package conversion
+
+import "fmt"
+
+// Policies for the empty types, i.e. the structs without any field, or without any field left once the fields
+// ignored by their tag are left out. A GraphQL type must define at least one field.
+const (
+	// EmptyPolicySkip leaves out the empty types, and the fields referencing them
+	EmptyPolicySkip = "skip"
+	// EmptyPolicyPlaceholder adds the EmptyPlaceholderField to the empty types
+	EmptyPolicyPlaceholder = "placeholder"
+	// EmptyPolicyScalar converts the empty types into custom scalars of the same name
+	EmptyPolicyScalar = "scalar"
+)
+
+// EmptyPlaceholderField is the name of the Boolean field added to the empty types with EmptyPolicyPlaceholder.
+const EmptyPlaceholderField = "_empty"
+
+// validateEmptyPolicy returns an error if the empty types policy is not valid.
+func validateEmptyPolicy(policy string) error {
+	switch policy {
+	case "", EmptyPolicySkip, EmptyPolicyPlaceholder, EmptyPolicyScalar:
+		return nil
+	}
+	return fmt.Errorf("%v: empty types policy %q is neither %s, %s nor %s", InvalidOptionErr, policy, EmptyPolicySkip, EmptyPolicyPlaceholder, EmptyPolicyScalar)
+}
+
+// applyEmptyPolicy applies the empty types policy of the options to the types resolved.
+func (r *schemaResolver) applyEmptyPolicy() {
+	switch r.opts.EmptyPolicy {
+	case EmptyPolicyPlaceholder:
+		for idx := range r.types {
+			if len(r.types[idx].Fields) == 0 {
+				r.types[idx].Fields = []GqlSchemaField{{Name: EmptyPlaceholderField, Type: "Boolean"}}
+			}
+		}
+	case EmptyPolicyScalar:
+		var schemaTypes []GqlSchemaType
+		for _, schemaType := range r.types {
+			if len(schemaType.Fields) == 0 {
+				r.scalars[schemaType.Name] = true
+				if _, ok := r.scalarDefs[schemaType.Name]; !ok {
+					r.scalarDefs[schemaType.Name] = GqlSchemaScalar{Name: schemaType.Name, GoModel: schemaType.GoModel}
+				}
+				continue
+			}
+			schemaTypes = append(schemaTypes, schemaType)
+		}
+		r.types = schemaTypes
+	case EmptyPolicySkip:
+		// Leaving out the fields referencing an empty type may empty other types
+		for {
+			skipped := make(map[string]bool)
+			var schemaTypes []GqlSchemaType
+			for _, schemaType := range r.types {
+				if len(schemaType.Fields) == 0 {
+					skipped[schemaType.Name] = true
+					continue
+				}
+				schemaTypes = append(schemaTypes, schemaType)
+			}
+			if len(skipped) == 0 {
+				break
+			}
+			for idx := range schemaTypes {
+				var fields []GqlSchemaField
+				for _, field := range schemaTypes[idx].Fields {
+					if !skipped[namedGqlType(field.Type)] {
+						fields = append(fields, field)
+					}
+				}
+				schemaTypes[idx].Fields = fields
+			}
+			r.types = schemaTypes
+		}
+		// The scalars only used by the fields left out are not needed anymore
+		r.removeUnusedScalars()
+	}
+}
+
+// removeUnusedScalars removes the scalars no field of the schema uses anymore.
+func (r *schemaResolver) removeUnusedScalars() {
+	used := make(map[string]bool)
+	for _, schemaType := range r.types {
+		for _, field := range schemaType.Fields {
+			used[namedGqlType(field.Type)] = true
+		}
+	}
+	for name := range r.scalars {
+		if !used[name] {
+			delete(r.scalars, name)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package conversion
+
+import "testing"
+
+// TestEmptyPolicy is a unit test for the empty types policies of ResolveGqlSchema.
+func TestEmptyPolicy(t *testing.T) {
+	ignored := "-"
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "Another"},
+		{GqlTypeName: "Hidden", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Secret", GqlFieldType: "String", GqlFieldTags: `json:"-"`}}},
+		{GqlTypeName: "Wrapper", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Hidden", GqlFieldType: "[Hidden]", GqlFieldTags: `json:"hidden"`}}},
+		{GqlTypeName: "User", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "Name", GqlFieldType: "String", GqlFieldTags: `json:"name"`},
+			{GqlFieldName: "Another", GqlFieldType: "Another", GqlFieldTags: `json:"another"`},
+			{GqlFieldName: "Wrapper", GqlFieldType: "Wrapper", GqlFieldTags: `json:"wrapper"`},
+		}},
+	}
+	tests := []struct {
+		policy  string
+		want    string
+		wantErr bool
+	}{
+		{
+			policy: "",
+			want: "\ntype Another {\n}\n\ntype Hidden {\n}\n\ntype User {\n  name: String\n  another: Another\n  wrapper: Wrapper\n}\n\n" +
+				"type Wrapper {\n  hidden: [Hidden]\n}\n\n",
+		},
+		{
+			// Wrapper is empty once its field referencing Hidden is left out
+			policy: EmptyPolicySkip,
+			want:   "\ntype User {\n  name: String\n}\n\n",
+		},
+		{
+			policy: EmptyPolicyPlaceholder,
+			want: "\ntype Another {\n  _empty: Boolean\n}\n\ntype Hidden {\n  _empty: Boolean\n}\n\ntype User {\n  name: String\n  another: Another\n" +
+				"  wrapper: Wrapper\n}\n\ntype Wrapper {\n  hidden: [Hidden]\n}\n\n",
+		},
+		{
+			policy: EmptyPolicyScalar,
+			want:   "scalar Another\nscalar Hidden\n\ntype User {\n  name: String\n  another: Another\n  wrapper: Wrapper\n}\n\ntype Wrapper {\n  hidden: [Hidden]\n}\n\n",
+		},
+		{policy: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			schema, err := ResolveGqlSchema(gqlTypeDefs, &PrettyPrintOptions{UseJsonTags: true, TagFieldToIgnore: &ignored, EmptyPolicy: tt.policy})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveGqlSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && schema.String() != tt.want {
+				t.Errorf("ResolveGqlSchema() = %q, want %q", schema.String(), tt.want)
+			}
+		})
+	}
+}
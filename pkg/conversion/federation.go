@@ -0,0 +1,193 @@
+package conversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FederationURL is the URL of the Apollo Federation specification the schema links to, see
+// https://www.apollographql.com/docs/federation/federated-schemas/federated-directives
+const FederationURL = "https://specs.apollo.dev/federation/v2.3"
+
+// Directives of Apollo Federation
+const (
+	// KeyDirective makes an object type an entity, identified by the fields of its fields argument
+	KeyDirective = "key"
+	// ShareableDirective lets several subgraphs resolve an object type or field
+	ShareableDirective = "shareable"
+	// ExternalDirective marks a field resolved by another subgraph
+	ExternalDirective = "external"
+	// LinkDirective links the schema to the federation specification, importing the federation directives it uses
+	LinkDirective = "link"
+)
+
+// federationTagValues are the values of the GqlTag adding a federation directive to a field: `gql:"key"` makes the
+// field part of the key of its type, `gql:"shareable"` and `gql:"external"` add the directive to the field.
+var federationTagValues = []string{KeyDirective, ShareableDirective, ExternalDirective}
+
+// applyFederation adds the Apollo Federation directives to the object types and their fields:
+// - @key(fields: "...") with the fields tagged `gql:"key"`, and with the fields of each //gql:key doc directive of
+// the struct, e.g. //gql:key id or //gql:key sku { id }
+// - @shareable to the structs with a //gql:shareable doc directive and to the fields tagged `gql:"shareable"`
+// - @external to the fields tagged `gql:"external"`
+//
+// The schema links to the FederationURL, importing the directives used. A //gql:key doc directive without fields is
+// an error.
+func (r *schemaResolver) applyFederation() error {
+	used := make(map[string]bool)
+	for idx := range r.types {
+		schemaType := &r.types[idx]
+		if schemaType.IsInput() || schemaType.Extend {
+			continue
+		}
+		var keys, keyFields []string
+		shareable := false
+		for fieldIdx := range schemaType.Fields {
+			field := &schemaType.Fields[fieldIdx]
+			for _, value := range r.federationFields[schemaType.Name+"."+field.Name] {
+				if value == KeyDirective {
+					keyFields = append(keyFields, field.Name)
+					continue
+				}
+				field.Directives = append(field.Directives, GqlSchemaDirective{Name: value})
+				used[value] = true
+			}
+		}
+		if len(keyFields) != 0 {
+			keys = append(keys, strings.Join(keyFields, " "))
+		}
+		for _, directive := range r.docDirectives[schemaType.Name] {
+			name, fields, _ := strings.Cut(directive, " ")
+			switch name {
+			case KeyDirective:
+				if strings.TrimSpace(fields) == "" {
+					return fmt.Errorf("the %s%s doc directive of type %s has no fields", DocDirectivePrefix, KeyDirective, schemaType.Name)
+				}
+				keys = append(keys, strings.TrimSpace(fields))
+			case ShareableDirective:
+				shareable = true
+			}
+		}
+		for _, key := range keys {
+			schemaType.Directives = append(schemaType.Directives, GqlSchemaDirective{
+				Name:      KeyDirective,
+				Arguments: []GqlSchemaDirectiveArg{{Name: "fields", Value: strconv.Quote(key)}},
+			})
+			used[KeyDirective] = true
+		}
+		if shareable {
+			schemaType.Directives = append(schemaType.Directives, GqlSchemaDirective{Name: ShareableDirective})
+			used[ShareableDirective] = true
+		}
+	}
+
+	link := GqlSchemaDirective{Name: LinkDirective, Arguments: []GqlSchemaDirectiveArg{{Name: "url", Value: strconv.Quote(FederationURL)}}}
+	var imports []string
+	for _, name := range federationTagValues {
+		if used[name] {
+			imports = append(imports, strconv.Quote("@"+name))
+		}
+	}
+	if len(imports) != 0 {
+		link.Arguments = append(link.Arguments, GqlSchemaDirectiveArg{Name: "import", Value: "[" + strings.Join(imports, ", ") + "]"})
+	}
+	r.schemaDirectives = append(r.schemaDirectives, link)
+	return nil
+}
+
+// ValidateFederation validates the entities of the GqlSchema, the object types with a @key directive, against the
+// requirements of Apollo Federation for the _Entity union, and returns the violations found:
+// - the fields argument of each key is a selection set of the fields of the entity, e.g. id or sku { id }
+// - a selected field takes no argument, e.g. it is not a connection
+// - a selected field whose type is an object type has a selection set of its own, and a scalar field has none
+//
+// The violations are warnings, or errors when strict is true. They are positioned at the Go struct of the type, if known.
+func ValidateFederation(schema *GqlSchema, strict bool) Diagnostics {
+	v := schemaValidator{severity: SeverityWarning}
+	if strict {
+		v.severity = SeverityError
+	}
+	types := make(map[string]GqlSchemaType, len(schema.Types))
+	for _, schemaType := range schema.Types {
+		if !schemaType.IsInput() && !schemaType.Extend {
+			types[schemaType.Name] = schemaType
+		}
+	}
+
+	for _, schemaType := range schema.Types {
+		for _, directive := range schemaType.Directives {
+			if directive.Name != KeyDirective {
+				continue
+			}
+			for _, arg := range directive.Arguments {
+				fields, err := strconv.Unquote(arg.Value)
+				if arg.Name != "fields" || err != nil {
+					continue
+				}
+				if message := validateKeyFields(schemaType, fields, types); message != "" {
+					v.report(schemaType, "", CodeFederation, fmt.Sprintf("invalid key %q: %s", fields, message))
+				}
+			}
+		}
+	}
+	return v.diagnostics
+}
+
+// validateKeyFields returns why the selection set fields is not a valid key of the entity, or an empty string if it is.
+func validateKeyFields(entity GqlSchemaType, fields string, types map[string]GqlSchemaType) string {
+	tokens := strings.Fields(strings.NewReplacer("{", " { ", "}", " } ").Replace(fields))
+	if len(tokens) == 0 {
+		return "no field selected"
+	}
+	next, message := validateSelectionSet(entity, tokens, types)
+	if message == "" && next != len(tokens) {
+		message = "unbalanced braces"
+	}
+	return message
+}
+
+// validateSelectionSet validates the selection set of the fields of the schemaType starting with the tokens, up to its
+// closing brace, and returns the number of tokens it is made of, and why it is not valid, if it is not.
+func validateSelectionSet(schemaType GqlSchemaType, tokens []string, types map[string]GqlSchemaType) (int, string) {
+	fields := make(map[string]GqlSchemaField, len(schemaType.Fields))
+	for _, field := range schemaType.Fields {
+		fields[field.Name] = field
+	}
+	idx := 0
+	for idx < len(tokens) && tokens[idx] != "}" {
+		name := tokens[idx]
+		field, ok := fields[name]
+		switch {
+		case name == "{":
+			return idx, fmt.Sprintf("unexpected { in the selection of %s", schemaType.Name)
+		case !ok:
+			return idx, fmt.Sprintf("%s has no field %s", schemaType.Name, name)
+		case len(field.Arguments) != 0:
+			return idx, fmt.Sprintf("the field %s.%s takes arguments", schemaType.Name, name)
+		}
+		idx++
+		object, isObject := types[namedGqlType(field.Type)]
+		hasSelection := idx < len(tokens) && tokens[idx] == "{"
+		switch {
+		case isObject && !hasSelection:
+			return idx, fmt.Sprintf("the field %s.%s of object type %s needs a selection of its fields", schemaType.Name, name, object.Name)
+		case !isObject && hasSelection:
+			return idx, fmt.Sprintf("the field %s.%s of type %s has no field to select", schemaType.Name, name, field.Type)
+		case hasSelection:
+			size, message := validateSelectionSet(object, tokens[idx+1:], types)
+			if message != "" {
+				return idx, message
+			}
+			idx += size + 1
+			if idx == len(tokens) {
+				return idx, "unbalanced braces"
+			}
+			idx++
+		}
+	}
+	if idx == 0 {
+		return idx, fmt.Sprintf("empty selection of %s", schemaType.Name)
+	}
+	return idx, ""
+}
@@ -0,0 +1,100 @@
+package conversion
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFederation is a unit test for the Apollo Federation directives of ResolveGqlSchema.
+func TestFederation(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "Product", GqlTypeDirectives: []string{"key upc variant { id }", ShareableDirective}, GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "ID", GqlFieldType: "String", GqlFieldTags: `json:"id" gql:"key"`},
+			{GqlFieldName: "Upc", GqlFieldType: "String", GqlFieldTags: `json:"upc"`},
+			{GqlFieldName: "Variant", GqlFieldType: "Variant", GqlFieldTags: `json:"variant"`},
+			{GqlFieldName: "Price", GqlFieldType: "Int", GqlFieldTags: `json:"price" gql:"shareable"`},
+			{GqlFieldName: "Weight", GqlFieldType: "Int", GqlFieldTags: `json:"weight" gql:"external"`},
+		}},
+		{GqlTypeName: "Variant", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "ID", GqlFieldType: "String", GqlFieldTags: `json:"id"`}}},
+	}
+	link := `extend schema @link(url: "https://specs.apollo.dev/federation/v2.3"`
+	tests := []struct {
+		name    string
+		defs    []GqlTypeDefinition
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Directives",
+			defs: gqlTypeDefs,
+			want: link + `, import: ["@key", "@shareable", "@external"])` + "\n\n" +
+				"type Product @key(fields: \"id\") @key(fields: \"upc variant { id }\") @shareable {\n" +
+				"  id: String\n  upc: String\n  variant: Variant\n  price: Int @shareable\n  weight: Int @external\n}\n\n" +
+				"type Variant {\n  id: String\n}\n\n",
+		},
+		{
+			name: "NoEntity",
+			defs: []GqlTypeDefinition{{GqlTypeName: "Variant", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "ID", GqlFieldType: "String"}}}},
+			want: link + ")\n\ntype Variant {\n  ID: String\n}\n\n",
+		},
+		{
+			name:    "KeyWithoutFields",
+			defs:    []GqlTypeDefinition{{GqlTypeName: "Variant", GqlTypeDirectives: []string{KeyDirective}, GqlFields: []GqlFieldsDefinition{{GqlFieldName: "ID", GqlFieldType: "String"}}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ResolveGqlSchema(tt.defs, &PrettyPrintOptions{UseJsonTags: true, Federation: true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveGqlSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && schema.String() != tt.want {
+				t.Errorf("ResolveGqlSchema() = %q, want %q", schema.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateFederation is a unit test for the ValidateFederation function.
+func TestValidateFederation(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "id"},
+		{key: "id variant { id }"},
+		{key: "sku", want: "Product has no field sku"},
+		{key: "variant", want: "the field Product.variant of object type Variant needs a selection of its fields"},
+		{key: "id { id }", want: "the field Product.id of type String has no field to select"},
+		{key: "variant { sku }", want: "Variant has no field sku"},
+		{key: "variant { id", want: "unbalanced braces"},
+		{key: "id }", want: "unbalanced braces"},
+		{key: "variant { }", want: "empty selection of Variant"},
+		{key: "related", want: "the field Product.related takes arguments"},
+		{key: "", want: "no field selected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			schema := &GqlSchema{Types: []GqlSchemaType{
+				{Name: "Product", Directives: []GqlSchemaDirective{{Name: KeyDirective, Arguments: []GqlSchemaDirectiveArg{{Name: "fields", Value: `"` + tt.key + `"`}}}}, Fields: []GqlSchemaField{
+					{Name: "id", Type: "String"},
+					{Name: "variant", Type: "Variant"},
+					{Name: "related", Type: "ProductConnection", Arguments: connectionArguments},
+				}},
+				{Name: "Variant", Fields: []GqlSchemaField{{Name: "id", Type: "String"}}},
+			}}
+			diagnostics := ValidateFederation(schema, true)
+			if tt.want == "" {
+				if len(diagnostics) != 0 {
+					t.Errorf("ValidateFederation() = %v, want no diagnostic", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].Severity != SeverityError || !strings.HasSuffix(diagnostics[0].Message, ": "+tt.want) {
+				t.Errorf("ValidateFederation() = %v, want an error %q", diagnostics, tt.want)
+			}
+		})
+	}
+}
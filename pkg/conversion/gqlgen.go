@@ -0,0 +1,217 @@
+package conversion
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// Directives of gqlgen binding the GraphQL types to Go types, see https://gqlgen.com/config/#inline-config-with-directives
+const (
+	// GoModelDirective binds a type to the Go type of its model argument, qualified by its import path
+	GoModelDirective = "goModel"
+	// GoFieldDirective binds a field to the Go struct field of its name argument
+	GoFieldDirective = "goField"
+)
+
+// gqlgenDirectiveDefinitions are the definitions of the gqlgen directives, as gqlgen documents them.
+var gqlgenDirectiveDefinitions = map[string]string{
+	GoModelDirective: "directive @goModel(model: String, models: [String!], forceGenerate: Boolean) on OBJECT | INPUT_OBJECT | SCALAR | ENUM | INTERFACE | UNION",
+	GoFieldDirective: "directive @goField(forceResolver: Boolean, name: String, omittable: Boolean) on INPUT_FIELD_DEFINITION | FIELD_DEFINITION",
+}
+
+// applyGoModelDirectives adds the @goModel directive to the types resolved from a Go struct, and the @goField directive
+// to their fields gqlgen would not bind to the Go struct field by name, see EqualGqlgenFieldName, so that gqlgen binds
+// them to the Go structs rather than generating its own models. The fields taking arguments, e.g. a connection, and
+// the inferred relations get @goField(forceResolver: true) instead, as they are resolved rather than bound. The
+// definitions of the directives used are added to the schema.
+func (r *schemaResolver) applyGoModelDirectives() {
+	var goModelUsed, goFieldUsed bool
+	for idx := range r.types {
+		schemaType := &r.types[idx]
+		// A type without Go struct, e.g. one built from a map, or whose fields are not Go struct fields, e.g. the
+		// placeholder of an empty type, is left to the model gqlgen generates
+		if schemaType.GoModel == "" || !hasGoFields(*schemaType) {
+			continue
+		}
+		schemaType.Directives = append(schemaType.Directives, GqlSchemaDirective{
+			Name:      GoModelDirective,
+			Arguments: []GqlSchemaDirectiveArg{{Name: "model", Value: strconv.Quote(schemaType.GoModel)}},
+		})
+		goModelUsed = true
+		for fieldIdx := range schemaType.Fields {
+			field := &schemaType.Fields[fieldIdx]
+			if len(field.Arguments) != 0 || field.ForeignKey != "" {
+				field.Directives = append(field.Directives, GqlSchemaDirective{
+					Name:      GoFieldDirective,
+					Arguments: []GqlSchemaDirectiveArg{{Name: "forceResolver", Value: "true"}},
+				})
+				goFieldUsed = true
+				continue
+			}
+			// A field without Go counterpart is left to a resolver, and gqlgen binds the one named like its Go field,
+			// ignoring the case and the underscores, by itself
+			if field.GoName == "" || EqualGqlgenFieldName(field.Name, field.GoName) {
+				continue
+			}
+			field.Directives = append(field.Directives, GqlSchemaDirective{
+				Name:      GoFieldDirective,
+				Arguments: []GqlSchemaDirectiveArg{{Name: "name", Value: strconv.Quote(field.GoName)}},
+			})
+			goFieldUsed = true
+		}
+	}
+	if goModelUsed {
+		r.directives = append(r.directives, GqlSchemaDirectiveDefinition{Name: GoModelDirective, Definition: gqlgenDirectiveDefinitions[GoModelDirective]})
+	}
+	if goFieldUsed {
+		r.directives = append(r.directives, GqlSchemaDirectiveDefinition{Name: GoFieldDirective, Definition: gqlgenDirectiveDefinitions[GoFieldDirective]})
+	}
+}
+
+// hasGoFields returns true if any field of the type has a Go struct field.
+func hasGoFields(schemaType GqlSchemaType) bool {
+	for _, field := range schemaType.Fields {
+		if field.GoName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyGqlgenBinding simulates how gqlgen binds the fields of the types converted from a Go struct to the exported
+// fields and methods of the struct, and returns a Diagnostic for each field it would not bind to its Go struct field,
+// which then needs a @goField directive or a resolver:
+// - no field or method matches the name of the GraphQL field, e.g. a field named published_at from its JSON tag
+// - another field or method matches it, e.g. a field named id from the JSON tag of the UserID field when the struct has an ID method
+// - several fields or methods match it, which gqlgen reports as ambiguous
+// - the field has no exported Go struct field, e.g. the placeholder of an empty type
+// - its type is a type without Go struct, e.g. one built from a map, for which gqlgen generates a model of its own
+//
+// Like gqlgen, the names match when they are equal ignoring the case and the underscores, and the fields promoted from
+// an embedded struct are only matched when no field or method of the struct itself does. The name of a @goField
+// directive is matched instead of the name of the field, and a field with @goField(forceResolver: true) is left to its
+// resolver. The methods are not matched for an input object type.
+// The Diagnostics are warnings, or errors when strict is true. They are positioned at the Go struct of the type, if known.
+func VerifyGqlgenBinding(schema *GqlSchema, strict bool) Diagnostics {
+	severity := SeverityWarning
+	if strict {
+		severity = SeverityError
+	}
+	generated := make(map[string]bool)
+	for _, schemaType := range schema.Types {
+		if schemaType.GoType == nil {
+			generated[schemaType.Name] = true
+		}
+	}
+
+	var diagnostics Diagnostics
+	for _, schemaType := range schema.Types {
+		if schemaType.GoType == nil {
+			continue
+		}
+		for _, field := range schemaType.Fields {
+			message := verifyFieldBinding(schemaType, field, generated)
+			if message == "" {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity:   severity,
+				Code:       CodeGqlgenBinding,
+				Position:   schemaType.fieldPosition(field.Name),
+				StructName: schemaType.Name,
+				FieldName:  field.Name,
+				Message:    message,
+			})
+		}
+	}
+	return diagnostics
+}
+
+// verifyFieldBinding returns why gqlgen would not bind the field of the type to its Go struct field, or an empty string
+// if it would.
+func verifyFieldBinding(schemaType GqlSchemaType, field GqlSchemaField, generated map[string]bool) string {
+	name := field.Name
+	for _, directive := range field.Directives {
+		if directive.Name != GoFieldDirective {
+			continue
+		}
+		for _, arg := range directive.Arguments {
+			if arg.Name == "forceResolver" && arg.Value == "true" {
+				return ""
+			}
+			if goName, err := strconv.Unquote(arg.Value); arg.Name == "name" && err == nil {
+				name = goName
+			}
+		}
+	}
+
+	if field.GoName == "" {
+		return "the field has no Go struct field, it needs a resolver"
+	}
+	if !token.IsExported(field.GoName) {
+		return fmt.Sprintf("gqlgen does not bind the unexported Go field %s, it needs a resolver", field.GoName)
+	}
+	if fieldType := namedGqlType(field.Type); generated[fieldType] {
+		return fmt.Sprintf("gqlgen generates the model of %s, which is not the type of the Go field %s, it needs a resolver", fieldType, field.GoName)
+	}
+	targets := gqlgenBindTargets(schemaType.GoType, name, !schemaType.IsInput())
+	fix := fmt.Sprintf("it needs @goField(name: %q) or a resolver", field.GoName)
+	switch {
+	case len(targets) == 0:
+		return fmt.Sprintf("gqlgen binds no Go field or method to %s, %s", name, fix)
+	case len(targets) > 1:
+		return fmt.Sprintf("gqlgen finds several Go fields or methods to bind %s to: %s, %s", name, strings.Join(targets, ", "), fix)
+	case targets[0] != field.GoName:
+		return fmt.Sprintf("gqlgen binds %s to %s instead of the Go field %s, %s", name, targets[0], field.GoName, fix)
+	}
+	return ""
+}
+
+// gqlgenBindTargets returns the exported fields and methods of the Go type gqlgen binds the GraphQL field name to, the
+// methods suffixed with (). When none of the methods, if withMethods is true, and fields of the struct match, the fields
+// and methods promoted from its embedded structs are matched.
+func gqlgenBindTargets(goType types.Type, name string, withMethods bool) []string {
+	var targets []string
+	if named, ok := goType.(*types.Named); ok && withMethods {
+		for idx := 0; idx < named.NumMethods(); idx++ {
+			method := named.Method(idx)
+			if method.Exported() && EqualGqlgenFieldName(name, method.Name()) {
+				targets = append(targets, method.Name()+"()")
+			}
+		}
+	}
+	structType, ok := goType.Underlying().(*types.Struct)
+	if !ok {
+		return targets
+	}
+	for idx := 0; idx < structType.NumFields(); idx++ {
+		field := structType.Field(idx)
+		if field.Exported() && EqualGqlgenFieldName(name, field.Name()) {
+			targets = append(targets, field.Name())
+		}
+	}
+	if len(targets) != 0 {
+		return targets
+	}
+	for idx := 0; idx < structType.NumFields(); idx++ {
+		field := structType.Field(idx)
+		if !field.Embedded() {
+			continue
+		}
+		embeddedType := field.Type()
+		if pointer, ok := embeddedType.(*types.Pointer); ok {
+			embeddedType = pointer.Elem()
+		}
+		targets = append(targets, gqlgenBindTargets(embeddedType, name, withMethods)...)
+	}
+	return targets
+}
+
+// EqualGqlgenFieldName reports whether gqlgen binds the GraphQL field name to the Go field or method goName by name,
+// i.e. whether they are equal ignoring the case and the underscores.
+func EqualGqlgenFieldName(name string, goName string) bool {
+	return strings.EqualFold(strings.ReplaceAll(name, "_", ""), strings.ReplaceAll(goName, "_", ""))
+}
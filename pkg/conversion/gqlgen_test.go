@@ -0,0 +1,148 @@
+package conversion
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+// TestGoModelDirectives is a unit test for the gqlgen directives added by ResolveGqlSchema with GoModelDirectives.
+func TestGoModelDirectives(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "Article", GqlTypePackage: "github.com/acme/models", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "Title", GqlFieldType: "String"},
+			{GqlFieldName: "PublishedAt", GqlFieldType: "DateTime", IsCustomScalar: true, GqlFieldTags: `json:"published"`},
+			{GqlFieldName: "UpdatedAt", GqlFieldType: "String", GqlFieldTags: `json:"updated_at"`},
+			{GqlFieldName: "Labels", GqlFieldType: "LabelsMap", GqlFieldTags: `json:"labels"`, NestedCustomType: []GqlTypeDefinition{
+				{GqlTypeName: "LabelsMap", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "key", GqlFieldType: "String"}}},
+			}},
+		}},
+		{GqlTypeName: "Marker", GqlTypePackage: "github.com/acme/models"},
+	}
+	tests := []struct {
+		name string
+		opts PrettyPrintOptions
+		want string
+	}{
+		{
+			name: "Disabled",
+			opts: PrettyPrintOptions{UseJsonTags: true, EmptyPolicy: EmptyPolicyPlaceholder},
+			want: "scalar DateTime\n\ntype Article {\n  Title: String\n  published: DateTime\n  updated_at: String\n  labels: LabelsMap\n}\n\n" +
+				"type LabelsMap {\n  key: String\n}\n\ntype Marker {\n  _empty: Boolean\n}\n\n",
+		},
+		{
+			// The type built from the map has no Go struct to bind, and the placeholder of the empty type no Go field.
+			// gqlgen binds the fields named like their Go field, ignoring the case and the underscores
+			name: "JsonTags",
+			opts: PrettyPrintOptions{UseJsonTags: true, EmptyPolicy: EmptyPolicyPlaceholder, GoModelDirectives: true},
+			want: gqlgenDirectiveDefinitions[GoModelDirective] + "\n" + gqlgenDirectiveDefinitions[GoFieldDirective] + "\n\n" +
+				"scalar DateTime\n\ntype Article @goModel(model: \"github.com/acme/models.Article\") {\n  Title: String\n" +
+				"  published: DateTime @goField(name: \"PublishedAt\")\n  updated_at: String\n  labels: LabelsMap\n}\n\n" +
+				"type LabelsMap {\n  key: String\n}\n\ntype Marker {\n  _empty: Boolean\n}\n\n",
+		},
+		{
+			// The fields named after the Go fields need no @goField
+			name: "GoNames",
+			opts: PrettyPrintOptions{EmptyPolicy: EmptyPolicyPlaceholder, GoModelDirectives: true},
+			want: gqlgenDirectiveDefinitions[GoModelDirective] + "\n\nscalar DateTime\n\n" +
+				"type Article @goModel(model: \"github.com/acme/models.Article\") {\n  Title: String\n  PublishedAt: DateTime\n" +
+				"  UpdatedAt: String\n  Labels: LabelsMap\n}\n\ntype LabelsMap {\n  key: String\n}\n\ntype Marker {\n  _empty: Boolean\n}\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ResolveGqlSchema(gqlTypeDefs, &tt.opts)
+			if err != nil {
+				t.Fatalf("ResolveGqlSchema() error = %v", err)
+			}
+			if got := schema.String(); got != tt.want {
+				t.Errorf("ResolveGqlSchema() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestVerifyGqlgenBinding is a unit test for the VerifyGqlgenBinding function.
+func TestVerifyGqlgenBinding(t *testing.T) {
+	const source = `package models
+
+type Base struct{ ID string }
+
+type User struct {
+	Base
+	UserID      string
+	PublishedAt int
+	Nick        string
+	Labels      map[string]string
+	secret      string
+	Name        string
+	NAME        string
+}
+
+func (u *User) Nickname() string { return u.Nick }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "models.go", source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := (&types.Config{}).Check("github.com/acme/models", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goType := pkg.Scope().Lookup("User").Type()
+	fields := []GqlSchemaField{
+		{Name: "id", Type: "String", GoName: "UserID"},
+		{Name: "ID", Type: "String", GoName: "ID"},
+		{Name: "published_at", Type: "Int", GoName: "PublishedAt"},
+		{Name: "nickname", Type: "String", GoName: "Nick"},
+		{Name: "labels", Type: "[LabelsMap]", GoName: "Labels"},
+		{Name: "secret", Type: "String", GoName: "secret"},
+		{Name: "name", Type: "String", GoName: "Name"},
+		{Name: "_empty", Type: "Boolean"},
+		{Name: "userId", Type: "String", GoName: "UserID", Directives: []GqlSchemaDirective{
+			{Name: GoFieldDirective, Arguments: []GqlSchemaDirectiveArg{{Name: "name", Value: `"UserID"`}}},
+		}},
+		// A connection is left to its resolver
+		{Name: "friends", Type: "LabelsMapConnection", GoName: "Friends", Directives: []GqlSchemaDirective{
+			{Name: GoFieldDirective, Arguments: []GqlSchemaDirectiveArg{{Name: "forceResolver", Value: "true"}}},
+		}},
+	}
+	schema := &GqlSchema{Types: []GqlSchemaType{
+		{Name: "User", GoModel: "github.com/acme/models.User", GoType: goType, Fields: fields},
+		// The methods are not bound to the fields of an input type
+		{Name: "UserInput", Kind: KindInput, GoModel: "github.com/acme/models.User", GoType: goType, Fields: fields[3:4]},
+		{Name: "LabelsMap", Fields: []GqlSchemaField{{Name: "key", Type: "String", GoName: "key"}}},
+	}}
+	wantMessages := map[string]string{
+		"User.id":            `gqlgen binds id to ID instead of the Go field UserID, it needs @goField(name: "UserID") or a resolver`,
+		"User.nickname":      `gqlgen binds nickname to Nickname() instead of the Go field Nick, it needs @goField(name: "Nick") or a resolver`,
+		"User.labels":        "gqlgen generates the model of LabelsMap, which is not the type of the Go field Labels, it needs a resolver",
+		"User.secret":        "gqlgen does not bind the unexported Go field secret, it needs a resolver",
+		"User.name":          `gqlgen finds several Go fields or methods to bind name to: Name, NAME, it needs @goField(name: "Name") or a resolver`,
+		"User._empty":        "the field has no Go struct field, it needs a resolver",
+		"UserInput.nickname": `gqlgen binds no Go field or method to nickname, it needs @goField(name: "Nick") or a resolver`,
+	}
+
+	diagnostics := VerifyGqlgenBinding(schema, false)
+	gotMessages := make(map[string]string)
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != SeverityWarning || diagnostic.Code != CodeGqlgenBinding {
+			t.Errorf("VerifyGqlgenBinding() diagnostic = %v, want a %s warning", diagnostic, CodeGqlgenBinding)
+		}
+		gotMessages[diagnostic.StructName+"."+diagnostic.FieldName] = diagnostic.Message
+	}
+	if !reflect.DeepEqual(gotMessages, wantMessages) {
+		t.Errorf("VerifyGqlgenBinding() = %v, want %v", gotMessages, wantMessages)
+	}
+
+	for _, diagnostic := range VerifyGqlgenBinding(schema, true) {
+		if diagnostic.Severity != SeverityError {
+			t.Errorf("VerifyGqlgenBinding() strict diagnostic = %v, want an error", diagnostic)
+		}
+	}
+}
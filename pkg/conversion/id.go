@@ -0,0 +1,120 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/structtag"
+)
+
+// IDType is the GraphQL type of the identifiers.
+const IDType = "ID"
+
+// TypeTagOption is the option of the GqlTag overriding the GraphQL type of a field, e.g. `gql:"type=ID"`. ID is the
+// only type it supports.
+const TypeTagOption = "type"
+
+// IDRules represents the rules selecting the fields converted into the IDType, besides the ones tagged `gql:"type=ID"`.
+// It contains the following fields:
+// - Names: the names of the Go fields converted into an ID, compared ignoring case, e.g. ID also matches Id
+// - References: a bool indicating whether the Go fields named after an object type of the schema followed by ID or Id,
+// e.g. AuthorID when there is an Author type, are converted into an ID
+//
+// Only the fields of type Int, String or BigInt, or lists of these types, are converted by these rules, the other
+// fields are left as is.
+type IDRules struct {
+	Names      []string
+	References bool
+}
+
+// idConvertibleTypes are the GraphQL types of the fields the IDRules convert into an ID.
+var idConvertibleTypes = map[string]bool{"Int": true, "String": true, "BigInt": true}
+
+// gqlTagOption returns the value of the option key of the GqlTag of a field, e.g. ID for `gql:"type=ID"`, and true,
+// or false if the option is not set.
+func gqlTagOption(tags *structtag.Tags, key string) (string, bool) {
+	gqlTag, err := tags.Get(GqlTag)
+	if err != nil {
+		return "", false
+	}
+	for _, option := range append([]string{gqlTag.Name}, gqlTag.Options...) {
+		if value, ok := strings.CutPrefix(option, key+"="); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// idTagged returns true if the field fieldName of the type typeName is tagged `gql:"type=ID"`, or an error if it is
+// tagged with another TypeTagOption.
+func idTagged(tags *structtag.Tags, typeName string, fieldName string) (bool, error) {
+	value, ok := gqlTagOption(tags, TypeTagOption)
+	if !ok {
+		return false, nil
+	}
+	if value != IDType {
+		return false, fmt.Errorf("field %s of type %s is tagged %s:%q but %s is the only type supported", fieldName, typeName, GqlTag, TypeTagOption+"="+value, IDType)
+	}
+	return true, nil
+}
+
+// applyIDRules converts into the IDType the fields of the object and input types tagged `gql:"type=ID"`, and the
+// ones selected by the IDRules. A tagged field whose type is not a scalar, or a list of scalars, is an error. The
+// custom scalars no longer used once the fields are converted, e.g. BigInt, are left out of the schema.
+func (r *schemaResolver) applyIDRules() error {
+	objects := make(map[string]bool)
+	for _, schemaType := range r.types {
+		if !schemaType.IsInput() {
+			objects[schemaType.Name] = true
+		}
+	}
+	names := make(map[string]bool, len(r.opts.IDRules.Names))
+	for _, name := range r.opts.IDRules.Names {
+		names[strings.ToLower(name)] = true
+	}
+
+	replaced := false
+	for idx := range r.types {
+		schemaType := &r.types[idx]
+		for fieldIdx := range schemaType.Fields {
+			field := &schemaType.Fields[fieldIdx]
+			named := namedGqlType(field.Type)
+			if r.idFields[schemaType.Name+"."+field.Name] {
+				if !gqlBuiltinScalars[named] && !r.scalars[named] {
+					return fmt.Errorf("field %s of type %s is tagged %s:%q but its type %s is not a scalar", field.Name, schemaType.Name, GqlTag, TypeTagOption+"="+IDType, named)
+				}
+			} else if !idConvertibleTypes[named] || !r.isIDField(*field, names, objects) {
+				continue
+			}
+			if named != IDType {
+				field.Type = strings.Replace(field.Type, named, IDType, 1)
+				replaced = true
+			}
+		}
+	}
+	if replaced {
+		r.removeUnusedScalars()
+	}
+	return nil
+}
+
+// isIDField returns true if the IDRules select the field: its Go name, or its name if it has none, is one of the
+// names, or one of the objects followed by ID or Id when References is set.
+func (r *schemaResolver) isIDField(field GqlSchemaField, names map[string]bool, objects map[string]bool) bool {
+	goName := field.GoName
+	if goName == "" {
+		goName = field.Name
+	}
+	if names[strings.ToLower(goName)] {
+		return true
+	}
+	if !r.opts.IDRules.References {
+		return false
+	}
+	for _, suffix := range []string{"ID", "Id"} {
+		if object, ok := strings.CutSuffix(goName, suffix); ok && objects[object] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package conversion
+
+import "testing"
+
+// TestIDRules is a unit test for the conversion of the identifiers into IDs of ResolveGqlSchema.
+func TestIDRules(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "User", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "Id", GqlFieldType: "BigInt", IsCustomScalar: true, GqlFieldTags: `json:"id"`},
+		}},
+		{GqlTypeName: "Order", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "ID", GqlFieldType: "String", GqlFieldTags: `json:"id"`},
+			{GqlFieldName: "UserID", GqlFieldType: "Int", GqlFieldTags: `json:"user_id"`},
+			{GqlFieldName: "ItemIDs", GqlFieldType: "[Int]", GqlFieldTags: `json:"item_ids" gql:"type=ID"`},
+			{GqlFieldName: "PaymentID", GqlFieldType: "Int", GqlFieldTags: `json:"payment_id"`},
+		}},
+	}
+	user := "type User {\n  id: BigInt\n}\n\n"
+	userID := "type User {\n  id: ID\n}\n\n"
+	tests := []struct {
+		name    string
+		rules   IDRules
+		defs    []GqlTypeDefinition
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "TaggedOnly",
+			want: "scalar BigInt\n\ntype Order {\n  id: String\n  user_id: Int\n  item_ids: [ID]\n  payment_id: Int\n}\n\n" + user,
+		},
+		{
+			// BigInt is left out once no field uses it
+			name:  "Names",
+			rules: IDRules{Names: []string{"ID"}},
+			want:  "\ntype Order {\n  id: ID\n  user_id: Int\n  item_ids: [ID]\n  payment_id: Int\n}\n\n" + userID,
+		},
+		{
+			// PaymentID does not reference an object type
+			name:  "References",
+			rules: IDRules{Names: []string{"ID"}, References: true},
+			want:  "\ntype Order {\n  id: ID\n  user_id: ID\n  item_ids: [ID]\n  payment_id: Int\n}\n\n" + userID,
+		},
+		{
+			name: "TaggedObject",
+			defs: []GqlTypeDefinition{
+				{GqlTypeName: "User", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Name", GqlFieldType: "String"}}},
+				{GqlTypeName: "Order", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "User", GqlFieldType: "User", GqlFieldTags: `gql:"type=ID"`}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TaggedOtherType",
+			defs: []GqlTypeDefinition{
+				{GqlTypeName: "Order", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Total", GqlFieldType: "Int", GqlFieldTags: `gql:"type=Float"`}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs := gqlTypeDefs
+			if tt.defs != nil {
+				defs = tt.defs
+			}
+			schema, err := ResolveGqlSchema(defs, &PrettyPrintOptions{UseJsonTags: true, IDRules: tt.rules})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveGqlSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && schema.String() != tt.want {
+				t.Errorf("ResolveGqlSchema() = %q, want %q", schema.String(), tt.want)
+			}
+		})
+	}
+}
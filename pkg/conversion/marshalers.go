@@ -0,0 +1,116 @@
+package conversion
+
+import (
+	"fmt"
+	"github.com/VintageOps/structogqlgen/pkg/load"
+	"go/types"
+)
+
+// Marshalers that are detected, by order of precedence, when ConvertOptions.DetectMarshalers is set.
+const (
+	// MarshalerGqlgen is gqlgen's graphql.Marshaler (MarshalGQL) along with graphql.Unmarshaler (UnmarshalGQL), the type
+	// is converted into a custom scalar named after the type
+	MarshalerGqlgen = "graphql.Marshaler"
+	// MarshalerJson is encoding/json's json.Marshaler (MarshalJSON), the type is converted into a custom scalar named after the type
+	MarshalerJson = "json.Marshaler"
+	// MarshalerText is encoding's encoding.TextMarshaler (MarshalText), the type is converted into a String
+	MarshalerText = "encoding.TextMarshaler"
+)
+
+// Types of the signatures of the marshalers methods
+var (
+	byteSliceType  = types.NewSlice(types.Typ[types.Byte])
+	errorType      = types.Universe.Lookup("error").Type()
+	emptyInterface = types.NewInterfaceType(nil, nil)
+)
+
+// detectMarshaler returns the marshaler implemented by t or by a pointer to t, or an empty string if there is none.
+// Method sets are checked by name and signature so that the packages declaring the interfaces need not be imported:
+// MarshalGQL(io.Writer) with UnmarshalGQL(interface{}) error, MarshalJSON() ([]byte, error) and
+// MarshalText() ([]byte, error).
+func detectMarshaler(t types.Type) string {
+	methodSet := types.NewMethodSet(types.NewPointer(t))
+	switch {
+	case hasMethod(methodSet, "MarshalGQL", []types.Type{nil}, nil) && isIoWriter(methodParam(methodSet, "MarshalGQL")) &&
+		hasMethod(methodSet, "UnmarshalGQL", []types.Type{emptyInterface}, []types.Type{errorType}):
+		return MarshalerGqlgen
+	case hasMethod(methodSet, "MarshalJSON", nil, []types.Type{byteSliceType, errorType}):
+		return MarshalerJson
+	case hasMethod(methodSet, "MarshalText", nil, []types.Type{byteSliceType, errorType}):
+		return MarshalerText
+	}
+	return ""
+}
+
+// hasMethod reports whether the method set has a method with the given name, parameters and results types, compared
+// with types.Identical. A nil type matches any type.
+func hasMethod(methodSet *types.MethodSet, name string, params []types.Type, results []types.Type) bool {
+	selection := methodSet.Lookup(nil, name)
+	if selection == nil {
+		return false
+	}
+	sig, ok := selection.Type().(*types.Signature)
+	return ok && !sig.Variadic() && identicalTypes(sig.Params(), params) && identicalTypes(sig.Results(), results)
+}
+
+// identicalTypes reports whether the types of the tuple are identical to the provided types, nil matching any type.
+func identicalTypes(tuple *types.Tuple, want []types.Type) bool {
+	if tuple.Len() != len(want) {
+		return false
+	}
+	for i, wantType := range want {
+		if wantType != nil && !types.Identical(tuple.At(i).Type(), wantType) {
+			return false
+		}
+	}
+	return true
+}
+
+// methodParam returns the type of the first parameter of the method of the method set with the given name.
+func methodParam(methodSet *types.MethodSet, name string) types.Type {
+	return methodSet.Lookup(nil, name).Type().(*types.Signature).Params().At(0).Type()
+}
+
+// isIoWriter reports whether t is the io.Writer interface.
+func isIoWriter(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "io" && named.Obj().Name() == "Writer"
+}
+
+// isMarshalerStruct reports whether the struct implements a marshaler, when marshalers detection is enabled, and
+// reports it as an info Diagnostic. Its values are serialized by the marshaler rather than by its fields, so the fields
+// of its type are converted according to the marshaler, e.g. into a custom scalar named after it, and the struct is
+// not converted into a type of its own.
+func (c *converter) isMarshalerStruct(structDef load.StructDiscovered) bool {
+	if !c.opts.DetectMarshalers || structDef.Name == nil {
+		return false
+	}
+	named, ok := structDef.Name.Type().(*types.Named)
+	if !ok {
+		return false
+	}
+	marshaler := detectMarshaler(named)
+	if marshaler == "" {
+		return false
+	}
+	if structDef.Fset != nil {
+		c.fset = structDef.Fset
+	}
+	c.context = conversionContext{structName: structDef.Name.Name(), pos: structDef.Name.Pos()}
+	var converted GqlFieldsDefinition
+	convertMarshalerType(named, marshaler, &converted)
+	c.diagnose(SeverityInfo, CodeMarshaler, fmt.Sprintf("%s implemented by %s, converted into %s where used rather than into a type", marshaler, named, converted.GqlFieldType))
+	return true
+}
+
+// convertMarshalerType converts a named type implementing a marshaler into a GqlFieldsDefinition.
+func convertMarshalerType(t *types.Named, marshaler string, gqlFieldDef *GqlFieldsDefinition) {
+	gqlFieldDef.GqlFieldMarshaler = marshaler
+	if marshaler == MarshalerText {
+		gqlFieldDef.GqlFieldType = "String"
+		return
+	}
+	gqlFieldDef.GqlFieldType = t.Obj().Name()
+	gqlFieldDef.IsCustomScalar = true
+	gqlFieldDef.GqlFieldGoType = qualifiedTypeName(t)
+}
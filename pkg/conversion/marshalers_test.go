@@ -0,0 +1,160 @@
+package conversion
+
+import (
+	"github.com/VintageOps/structogqlgen/pkg/load"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+// testMethod represents a method of a named type built by newNamedWithMethods.
+type testMethod struct {
+	name    string
+	params  []types.Type
+	results []types.Type
+}
+
+// Types of the signatures of the marshalers methods
+var (
+	ioWriterType = types.NewNamed(types.NewTypeName(token.NoPos, types.NewPackage("io", "io"), "Writer", nil), types.NewInterfaceType(nil, nil), nil)
+	marshalGQL   = testMethod{name: "MarshalGQL", params: []types.Type{ioWriterType}}
+	unmarshalGQL = testMethod{name: "UnmarshalGQL", params: []types.Type{emptyInterface}, results: []types.Type{errorType}}
+	marshalJSON  = testMethod{name: "MarshalJSON", results: []types.Type{byteSliceType, errorType}}
+	marshalText  = testMethod{name: "MarshalText", results: []types.Type{byteSliceType, errorType}}
+)
+
+// newNamedWithMethods returns a named type with underlying type underlying and the given methods.
+// The methods have a pointer receiver if ptrRecv is true.
+func newNamedWithMethods(name string, underlying types.Type, ptrRecv bool, methods ...testMethod) *types.Named {
+	pkg := types.NewPackage("some.pkg/path", "path")
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), underlying, nil)
+	vars := func(varTypes []types.Type) *types.Tuple {
+		v := make([]*types.Var, len(varTypes))
+		for i, varType := range varTypes {
+			v[i] = types.NewVar(token.NoPos, pkg, "", varType)
+		}
+		return types.NewTuple(v...)
+	}
+	var recvType types.Type = named
+	if ptrRecv {
+		recvType = types.NewPointer(named)
+	}
+	for _, method := range methods {
+		recv := types.NewVar(token.NoPos, pkg, "r", recvType)
+		sig := types.NewSignatureType(recv, nil, nil, vars(method.params), vars(method.results), false)
+		named.AddMethod(types.NewFunc(token.NoPos, pkg, method.name, sig))
+	}
+	return named
+}
+
+// TestDetectMarshalers is a unit test for the conversion of types implementing a marshaler.
+func TestDetectMarshalers(t *testing.T) {
+	structType := types.NewStruct(nil, nil)
+	stringType := types.Typ[types.String]
+	tests := []struct {
+		name          string
+		detect        bool
+		goType        types.Type
+		wantType      string
+		wantScalar    bool
+		wantMarshaler string
+	}{
+		{
+			name:          "GqlgenMarshaler",
+			detect:        true,
+			goType:        newNamedWithMethods("Money", structType, false, marshalGQL, unmarshalGQL),
+			wantType:      "Money",
+			wantScalar:    true,
+			wantMarshaler: MarshalerGqlgen,
+		},
+		{
+			name:          "JsonMarshalerPointerReceiver",
+			detect:        true,
+			goType:        newNamedWithMethods("Time", structType, true, marshalJSON),
+			wantType:      "Time",
+			wantScalar:    true,
+			wantMarshaler: MarshalerJson,
+		},
+		{
+			name:          "TextMarshaler",
+			detect:        true,
+			goType:        types.NewPointer(newNamedWithMethods("IP", types.NewSlice(types.Typ[types.Uint8]), false, marshalText)),
+			wantType:      "String",
+			wantMarshaler: MarshalerText,
+		},
+		{
+			// gqlgen needs both methods to bind a scalar
+			name:     "GqlgenMarshalerWithoutUnmarshaler",
+			detect:   true,
+			goType:   newNamedWithMethods("Money", structType, false, marshalGQL),
+			wantType: "Money",
+		},
+		{
+			name:     "MarshalGQLNotWriting",
+			detect:   true,
+			goType:   newNamedWithMethods("Money", structType, false, testMethod{name: "MarshalGQL", params: []types.Type{stringType}}, unmarshalGQL),
+			wantType: "Money",
+		},
+		{
+			name:     "WrongParams",
+			detect:   true,
+			goType:   newNamedWithMethods("Odd", structType, false, testMethod{name: "MarshalText", params: []types.Type{stringType}, results: marshalText.results}),
+			wantType: "Odd",
+		},
+		{
+			name:     "WrongResults",
+			detect:   true,
+			goType:   newNamedWithMethods("Odd", structType, false, testMethod{name: "MarshalJSON", results: []types.Type{stringType, errorType}}),
+			wantType: "Odd",
+		},
+		{
+			name:     "DetectionDisabled",
+			detect:   false,
+			goType:   newNamedWithMethods("Time", structType, false, marshalJSON),
+			wantType: "Time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GqlFieldsDefinition{GqlFieldName: "field"}
+			err := newConverter(&ConvertOptions{DetectMarshalers: tt.detect}).convertType(tt.goType, &got)
+			if err != nil {
+				t.Fatalf("convertType() error = %v", err)
+			}
+			if got.GqlFieldType != tt.wantType || got.IsCustomScalar != tt.wantScalar || got.GqlFieldMarshaler != tt.wantMarshaler {
+				t.Errorf("convertType() = %s (scalar %v, marshaler %q), want %s (scalar %v, marshaler %q)",
+					got.GqlFieldType, got.IsCustomScalar, got.GqlFieldMarshaler, tt.wantType, tt.wantScalar, tt.wantMarshaler)
+			}
+		})
+	}
+}
+
+// TestMarshalerStruct is a unit test for the structs implementing a marshaler of BuildGqlTypesWithOptions.
+func TestMarshalerStruct(t *testing.T) {
+	money := newNamedWithMethods("Money", types.NewStruct(nil, nil), true, marshalJSON)
+	orderFields := []*types.Var{types.NewVar(token.NoPos, nil, "Price", money)}
+	structsFound := []load.StructDiscovered{
+		{Name: money.Obj(), Obj: money.Underlying().(*types.Struct)},
+		{Name: types.NewTypeName(token.NoPos, nil, "Order", nil), Obj: types.NewStruct(orderFields, []string{""})},
+	}
+	tests := []struct {
+		detect    bool
+		wantTypes int
+	}{
+		{detect: true, wantTypes: 1},
+		{detect: false, wantTypes: 2},
+	}
+	for _, tt := range tests {
+		gqlTypeDefs, diagnostics, err := BuildGqlTypesWithOptions(structsFound, &ConvertOptions{DetectMarshalers: tt.detect})
+		if err != nil {
+			t.Fatalf("BuildGqlTypesWithOptions() error = %v", err)
+		}
+		if len(gqlTypeDefs) != tt.wantTypes || gqlTypeDefs[len(gqlTypeDefs)-1].GqlTypeName != "Order" {
+			t.Errorf("BuildGqlTypesWithOptions() detect %v = %v, want %d types", tt.detect, gqlTypeDefs, tt.wantTypes)
+		}
+		if tt.detect && (len(diagnostics) != 2 || diagnostics[0].String() != "info: Money: json.Marshaler implemented by some.pkg/path.Money, converted into Money where used rather than into a type [marshaler]") {
+			t.Errorf("BuildGqlTypesWithOptions() diagnostics = %v", diagnostics)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package conversion
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DocDirectivePrefix prefixes the lines of the doc comment of a struct giving instructions to the conversion, e.g.
+// //gql:entity.
+const DocDirectivePrefix = "//gql:"
+
+// EntityDocDirective marks a struct as an entity, whose CRUD operations are generated with the Operations option.
+const EntityDocDirective = "entity"
+
+// PluralDocDirective sets the plural of the name of an entity, naming its list query, e.g. //gql:plural People for a
+// Person entity, rather than the one following the regular English rules, see pluralize.
+const PluralDocDirective = "plural"
+
+// Names of the root operation types extended with the operations of the entities
+const (
+	QueryType    = "Query"
+	MutationType = "Mutation"
+)
+
+// docDirectives returns the directives of a doc comment, i.e. its lines starting with DocDirectivePrefix, without the
+// prefix, e.g. entity for //gql:entity.
+func docDirectives(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var directives []string
+	for _, comment := range doc.List {
+		if directive, ok := strings.CutPrefix(comment.Text, DocDirectivePrefix); ok {
+			directives = append(directives, strings.TrimSpace(directive))
+		}
+	}
+	return directives
+}
+
+// applyOperations extends the Query and Mutation types with the CRUD operations of the entities, the object types
+// whose struct is marked with EntityDocDirective. For an entity Article:
+// - Query gets article(id: ID!): Article and articles(limit: Int, offset: Int): [Article!]!, or a connection of
+// the articles with ConnectionsAll
+// - Mutation gets createArticle(input: ArticleInput!), updateArticle(id: ID!, input: ArticleInput!) and
+// deleteArticle(id: ID!), returning an ArticlePayload!
+//
+// The ArticlePayload type holds the article, and the ArticleInput input type the fields of Article but its id and
+// connections, the object types it references being replaced by input types of their own, e.g. AuthorInput.
+// It returns an error if an operation has the name of another, e.g. when the plural of an entity is its name, as for
+// News, which then needs a PluralDocDirective.
+func (r *schemaResolver) applyOperations() error {
+	objects := make(map[string]GqlSchemaType)
+	for _, schemaType := range r.types {
+		if !schemaType.IsInput() && !schemaType.Extend {
+			objects[schemaType.Name] = schemaType
+		}
+	}
+
+	query := GqlSchemaType{Name: QueryType, Extend: true}
+	mutation := GqlSchemaType{Name: MutationType, Extend: true}
+	inputs := &inputBuilder{objects: objects, built: make(map[string]bool)}
+	var payloads []GqlSchemaType
+	for _, entity := range r.types {
+		if !entity.Entity || entity.IsInput() {
+			continue
+		}
+		if query.Package == "" {
+			query.Package, mutation.Package = entity.Package, entity.Package
+		}
+		plural, err := r.entityPlural(entity.Name)
+		if err != nil {
+			return err
+		}
+		single := lowerFirst(entity.Name)
+		idArgument := GqlSchemaArgument{Name: "id", Type: "ID!"}
+		list := GqlSchemaField{Name: plural, Type: "[" + entity.Name + "!]", NonNull: true, Arguments: []GqlSchemaArgument{
+			{Name: "limit", Type: "Int"},
+			{Name: "offset", Type: "Int"},
+		}}
+		if r.opts.Connections == ConnectionsAll {
+			list = GqlSchemaField{Name: plural, Type: entity.Name + "Connection", NonNull: true, Arguments: connectionArguments}
+			r.connect(entity.Name, entity.Package)
+		}
+		query.Fields = append(query.Fields,
+			GqlSchemaField{Name: single, Type: entity.Name, Arguments: []GqlSchemaArgument{idArgument}},
+			list)
+
+		input := inputs.build(entity.Name)
+		payload := entity.Name + "Payload"
+		inputArgument := GqlSchemaArgument{Name: "input", Type: input + "!"}
+		mutation.Fields = append(mutation.Fields,
+			GqlSchemaField{Name: "create" + entity.Name, Type: payload, NonNull: true, Arguments: []GqlSchemaArgument{inputArgument}},
+			GqlSchemaField{Name: "update" + entity.Name, Type: payload, NonNull: true, Arguments: []GqlSchemaArgument{idArgument, inputArgument}},
+			GqlSchemaField{Name: "delete" + entity.Name, Type: payload, NonNull: true, Arguments: []GqlSchemaArgument{idArgument}})
+		payloads = append(payloads, GqlSchemaType{Name: payload, Package: entity.Package, Fields: []GqlSchemaField{
+			{Name: single, Type: entity.Name},
+		}})
+	}
+	if len(query.Fields) == 0 {
+		return nil
+	}
+	if err := checkQueryNames(query); err != nil {
+		return err
+	}
+	r.types = append(r.types, inputs.types...)
+	r.types = append(r.types, payloads...)
+	r.types = append(r.types, query, mutation)
+	return nil
+}
+
+// entityPlural returns the name of the list query of the entity typeName: the plural of its PluralDocDirective doc
+// directive, if any, or else the one of pluralize, with its first letter lowercased.
+func (r *schemaResolver) entityPlural(typeName string) (string, error) {
+	for _, directive := range r.docDirectives[typeName] {
+		name, plural, _ := strings.Cut(directive, " ")
+		if name != PluralDocDirective {
+			continue
+		}
+		plural = strings.TrimSpace(plural)
+		if !gqlNameRegexp.MatchString(plural) || strings.HasPrefix(plural, "__") {
+			return "", fmt.Errorf("invalid %s%s doc directive of type %s: %q is not a valid GraphQL name", DocDirectivePrefix, PluralDocDirective, typeName, plural)
+		}
+		return lowerFirst(plural), nil
+	}
+	return lowerFirst(pluralize(typeName)), nil
+}
+
+// checkQueryNames returns an error if two queries of the Query extension have the same name, e.g. the single and list
+// queries of an entity whose plural is its name. The names of the mutations are unique, as the names of the entities are.
+func checkQueryNames(query GqlSchemaType) error {
+	names := make(map[string]bool, len(query.Fields))
+	for _, field := range query.Fields {
+		if names[field.Name] {
+			return fmt.Errorf("the query %s is generated more than once, set the plural of the entity with a %s%s doc directive",
+				field.Name, DocDirectivePrefix, PluralDocDirective)
+		}
+		names[field.Name] = true
+	}
+	return nil
+}
+
+// inputBuilder builds the input types of object types, the input types of the object types they reference included.
+type inputBuilder struct {
+	objects map[string]GqlSchemaType // objects are the object types of the schema, by name
+	built   map[string]bool          // built are the names of the object types whose input type is built
+	types   []GqlSchemaType          // types are the input types built, in the order they are built
+}
+
+// build returns the name of the input type of the object type name, e.g. ArticleInput, building it if needed. Its
+// fields are the ones of the object type but the connections, and its id if the object type is an entity, whether the
+// input type is the one of its own operations or one referenced by the input type of another entity.
+func (b *inputBuilder) build(name string) string {
+	inputName := name + "Input"
+	if b.built[name] {
+		return inputName
+	}
+	b.built[name] = true
+	object := b.objects[name]
+	withoutID := object.Entity
+	idx := len(b.types)
+	b.types = append(b.types, GqlSchemaType{Name: inputName, Kind: KindInput, Package: object.Package})
+
+	var fields []GqlSchemaField
+	for _, field := range object.Fields {
+		if len(field.Arguments) != 0 || (withoutID && strings.EqualFold(field.Name, "id")) {
+			continue
+		}
+		inputField := GqlSchemaField{Name: field.Name, Type: field.Type, NonNull: field.NonNull, GoName: field.GoName, Position: field.Position}
+		if fieldType := namedGqlType(field.Type); b.objects[fieldType].Name != "" {
+			inputField.Type = strings.Replace(field.Type, fieldType, b.build(fieldType), 1)
+		}
+		fields = append(fields, inputField)
+	}
+	b.types[idx].Fields = fields
+	return inputName
+}
+
+// lowerFirst returns the name with its first letter lowercased, e.g. article for Article.
+func lowerFirst(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(first)) + name[size:]
+}
+
+// pluralize returns the English plural of the name, following the regular rules, e.g. Categories for Category.
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
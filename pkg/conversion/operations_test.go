@@ -0,0 +1,203 @@
+package conversion
+
+import (
+	"go/ast"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestOperations is a unit test for the CRUD operations of ResolveGqlSchema.
+func TestOperations(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "Author", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Name", GqlFieldType: "String", GqlFieldTags: `json:"name"`}}},
+		{GqlTypeName: "Category", GqlTypeDirectives: []string{EntityDocDirective}, GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "ID", GqlFieldType: "Int", GqlFieldTags: `json:"id"`},
+			{GqlFieldName: "Author", GqlFieldType: "Author", GqlFieldTags: `json:"author"`},
+			{GqlFieldName: "Children", GqlFieldType: "[Category]", GqlFieldTags: `json:"children" gql:"connection"`},
+		}},
+	}
+	tests := []struct {
+		name   string
+		policy string
+		want   []string
+	}{
+		{
+			name: "Tagged",
+			want: []string{
+				"Author", "AuthorInput", "Category", "CategoryConnection", "CategoryEdge", "CategoryInput", "CategoryPayload",
+				"extend type Mutation {\n  createCategory(input: CategoryInput!): CategoryPayload!\n" +
+					"  updateCategory(id: ID!, input: CategoryInput!): CategoryPayload!\n  deleteCategory(id: ID!): CategoryPayload!\n}\n",
+				"PageInfo",
+				"extend type Query {\n  category(id: ID!): Category\n  categories(limit: Int, offset: Int): [Category!]!\n}\n",
+			},
+		},
+		{
+			name:   "All",
+			policy: ConnectionsAll,
+			want: []string{
+				"Author", "AuthorInput", "Category", "CategoryConnection", "CategoryEdge", "CategoryInput", "CategoryPayload",
+				"extend type Mutation {\n  createCategory(input: CategoryInput!): CategoryPayload!\n" +
+					"  updateCategory(id: ID!, input: CategoryInput!): CategoryPayload!\n  deleteCategory(id: ID!): CategoryPayload!\n}\n",
+				"PageInfo",
+				"extend type Query {\n  category(id: ID!): Category\n" +
+					"  categories(first: Int, after: String, last: Int, before: String): CategoryConnection!\n}\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ResolveGqlSchema(gqlTypeDefs, &PrettyPrintOptions{UseJsonTags: true, Operations: true, Connections: tt.policy})
+			if err != nil {
+				t.Fatalf("ResolveGqlSchema() error = %v", err)
+			}
+			var got []string
+			for _, schemaType := range schema.Types {
+				if schemaType.Extend {
+					got = append(got, schemaType.String())
+				} else {
+					got = append(got, schemaType.Name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveGqlSchema() types = %q, want %q", got, tt.want)
+			}
+			// The input of an entity has neither its id nor its connections, and references the inputs of its objects
+			for _, schemaType := range schema.Types {
+				if schemaType.Name == "CategoryInput" {
+					if want := "input CategoryInput {\n  author: AuthorInput\n}\n"; schemaType.String() != want {
+						t.Errorf("ResolveGqlSchema() input = %q, want %q", schemaType.String(), want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestOperationsWithoutEntity checks that the Query and Mutation types are not extended if there is no entity.
+func TestOperationsWithoutEntity(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{{GqlTypeName: "Author", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Name", GqlFieldType: "String"}}}}
+	schema, err := ResolveGqlSchema(gqlTypeDefs, &PrettyPrintOptions{Operations: true})
+	if err != nil {
+		t.Fatalf("ResolveGqlSchema() error = %v", err)
+	}
+	if len(schema.Types) != 1 {
+		t.Errorf("ResolveGqlSchema() types = %v, want Author only", schema.Types)
+	}
+}
+
+// TestDocDirectives is a unit test for the docDirectives function.
+func TestDocDirectives(t *testing.T) {
+	doc := &ast.CommentGroup{List: []*ast.Comment{
+		{Text: "// Article is a blog post."},
+		{Text: "//"},
+		{Text: "//gql:entity"},
+		{Text: "// gql:ignored"},
+	}}
+	if got, want := docDirectives(doc), []string{EntityDocDirective}; !reflect.DeepEqual(got, want) {
+		t.Errorf("docDirectives() = %v, want %v", got, want)
+	}
+	if got := docDirectives(nil); got != nil {
+		t.Errorf("docDirectives(nil) = %v, want nil", got)
+	}
+}
+
+// TestPluralize is a unit test for the pluralize function.
+func TestPluralize(t *testing.T) {
+	tests := map[string]string{"Article": "Articles", "Category": "Categories", "Day": "Days", "Box": "Boxes", "Match": "Matches", "Bus": "Buses"}
+	for name, want := range tests {
+		if got := pluralize(name); got != want {
+			t.Errorf("pluralize(%s) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+// TestOperationsPlural is a unit test for the names of the list queries of the entities, and their PluralDocDirective.
+func TestOperationsPlural(t *testing.T) {
+	tests := []struct {
+		name       string
+		directives []string
+		other      string
+		want       string
+		wantErr    string
+	}{
+		{name: "Person", want: "person persons"},
+		{name: "Person", directives: []string{"plural People"}, want: "person people"},
+		{name: "Story", want: "story stories"},
+		{name: "Series", directives: []string{"plural SeriesList"}, want: "series seriesList"},
+		{name: "Series", directives: []string{"plural Series"}, wantErr: "the query series is generated more than once"},
+		{name: "Bus", other: "Buses", wantErr: "the query buses is generated more than once"},
+		{name: "Person", directives: []string{"plural"}, wantErr: `invalid //gql:plural doc directive of type Person: "" is not a valid GraphQL name`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+tt.other+strings.Join(tt.directives, ","), func(t *testing.T) {
+			fields := []GqlFieldsDefinition{{GqlFieldName: "Name", GqlFieldType: "String"}}
+			gqlTypeDefs := []GqlTypeDefinition{{GqlTypeName: tt.name, GqlTypeDirectives: append([]string{EntityDocDirective}, tt.directives...), GqlFields: fields}}
+			if tt.other != "" {
+				gqlTypeDefs = append(gqlTypeDefs, GqlTypeDefinition{GqlTypeName: tt.other, GqlTypeDirectives: []string{EntityDocDirective}, GqlFields: fields})
+			}
+			schema, err := ResolveGqlSchema(gqlTypeDefs, &PrettyPrintOptions{Operations: true})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveGqlSchema() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveGqlSchema() error = %v", err)
+			}
+			var got []string
+			for _, schemaType := range schema.Types {
+				if schemaType.Name == QueryType {
+					for _, field := range schemaType.Fields {
+						got = append(got, field.Name)
+					}
+				}
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("ResolveGqlSchema() queries = %q, want %q", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+}
+
+// TestOperationsInputOrder checks that the input type of an entity referenced by another entity has the same fields
+// whichever entity is processed first.
+func TestOperationsInputOrder(t *testing.T) {
+	author := GqlTypeDefinition{GqlTypeName: "Author", GqlTypeDirectives: []string{EntityDocDirective}, GqlFields: []GqlFieldsDefinition{
+		{GqlFieldName: "ID", GqlFieldType: "Int", GqlFieldTags: `json:"id"`},
+		{GqlFieldName: "Name", GqlFieldType: "String", GqlFieldTags: `json:"name"`},
+		{GqlFieldName: "Tag", GqlFieldType: "Tag", GqlFieldTags: `json:"tag"`},
+	}}
+	article := GqlTypeDefinition{GqlTypeName: "Article", GqlTypeDirectives: []string{EntityDocDirective}, GqlFields: []GqlFieldsDefinition{
+		{GqlFieldName: "ID", GqlFieldType: "Int", GqlFieldTags: `json:"id"`},
+		{GqlFieldName: "Author", GqlFieldType: "Author", GqlFieldTags: `json:"author"`},
+	}}
+	tag := GqlTypeDefinition{GqlTypeName: "Tag", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "ID", GqlFieldType: "Int", GqlFieldTags: `json:"id"`}}}
+	want := map[string]string{
+		"ArticleInput": "input ArticleInput {\n  author: AuthorInput\n}\n",
+		"AuthorInput":  "input AuthorInput {\n  name: String\n  tag: TagInput\n}\n",
+		// The input of an object type that is not an entity keeps its id
+		"TagInput": "input TagInput {\n  id: Int\n}\n",
+	}
+
+	for name, gqlTypeDefs := range map[string][]GqlTypeDefinition{"ArticleFirst": {article, author, tag}, "AuthorFirst": {author, article, tag}} {
+		t.Run(name, func(t *testing.T) {
+			schema, err := ResolveGqlSchema(gqlTypeDefs, &PrettyPrintOptions{UseJsonTags: true, Operations: true})
+			if err != nil {
+				t.Fatalf("ResolveGqlSchema() error = %v", err)
+			}
+			got := make(map[string]string)
+			for _, schemaType := range schema.Types {
+				if schemaType.IsInput() {
+					got[schemaType.Name] = schemaType.String()
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ResolveGqlSchema() inputs = %q, want %q", got, want)
+			}
+		})
+	}
+}
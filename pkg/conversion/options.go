@@ -0,0 +1,117 @@
+package conversion
+
+import (
+	"fmt"
+	"go/types"
+	"regexp"
+)
+
+// ConvertOptions represents the options used when converting Go types into GraphQL types. It contains the following fields:
+// - IntPolicy: a string selecting how integers that may not fit in a GraphQL Int are converted, see IntPolicyStrict,
+// IntPolicyLenient and IntPolicyString. Any other non-empty value is used as the name of the custom scalar to use, or of
+// the built-in scalar to use without declaring it, e.g. ID or Float. When empty, MapBasicKindToGqlType is used as is.
+// - UnwrapNamedBasic: a bool indicating whether named basic types (e.g. type Email string) are converted into their
+// base GraphQL type rather than into a custom scalar named after the type
+// - DetectMarshalers: a bool indicating whether types implementing a marshaler are converted according to that marshaler,
+// see MarshalerGqlgen, MarshalerJson and MarshalerText
+// - UnsupportedPolicy: a string selecting how fields of a type that GraphQL cannot represent (channels, funcs,
+// unsafe.Pointer and complex numbers) are converted, see UnsupportedPolicyError, UnsupportedPolicySkip and
+// UnsupportedPolicyScalar. When empty, channels and funcs are errors while unsafe.Pointer and complex numbers
+// are converted into custom scalars.
+// - TypeMappings: a map from Go named types, qualified by their import path or their package name
+// (e.g. github.com/acme/models.Status or time.Time), to the GraphQL type they are converted into. It takes precedence
+// over any other conversion rule, and the GraphQL type is a custom scalar unless it is a built-in scalar.
+type ConvertOptions struct {
+	IntPolicy         string
+	UnwrapNamedBasic  bool
+	DetectMarshalers  bool
+	UnsupportedPolicy string
+	TypeMappings      map[string]string
+}
+
+// Integer policies, GraphQL Int represents a signed 32‐bit integer
+const (
+	// IntPolicyStrict converts any integer type that can overflow a GraphQL Int into the BigInt custom scalar
+	IntPolicyStrict = "strict"
+	// IntPolicyLenient converts all integer types into a GraphQL Int
+	IntPolicyLenient = "lenient"
+	// IntPolicyString converts any integer type that can overflow a GraphQL Int into a GraphQL String
+	IntPolicyString = "string"
+)
+
+// gqlNameRegexp matches a valid GraphQL name, see https://spec.graphql.org/October2021/#Name
+var gqlNameRegexp = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// Validate returns an InvalidOptionErr error if the integer policy, the unsupported types policy or a type mapping is
+// not valid.
+func (opts *ConvertOptions) Validate() error {
+	switch opts.IntPolicy {
+	case "", IntPolicyStrict, IntPolicyLenient, IntPolicyString:
+	default:
+		if !gqlNameRegexp.MatchString(opts.IntPolicy) {
+			return fmt.Errorf("%v: integer policy %q is neither %s, %s, %s nor a valid scalar name",
+				InvalidOptionErr, opts.IntPolicy, IntPolicyStrict, IntPolicyLenient, IntPolicyString)
+		}
+	}
+	switch opts.UnsupportedPolicy {
+	case "", UnsupportedPolicyError, UnsupportedPolicySkip, UnsupportedPolicyScalar:
+	default:
+		return fmt.Errorf("%v: unsupported types policy %q is neither %s, %s nor %s",
+			InvalidOptionErr, opts.UnsupportedPolicy, UnsupportedPolicyError, UnsupportedPolicySkip, UnsupportedPolicyScalar)
+	}
+	for goType, gqlType := range opts.TypeMappings {
+		if !gqlNameRegexp.MatchString(gqlType) {
+			return fmt.Errorf("%v: type mapping of %s to %q is not a valid GraphQL type name", InvalidOptionErr, goType, gqlType)
+		}
+	}
+	return nil
+}
+
+// gqlBuiltinScalars are the scalars defined by the GraphQL specification, which need no custom scalar.
+var gqlBuiltinScalars = map[string]bool{"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true}
+
+// mappedGqlType returns the GraphQL type a named type is mapped to with TypeMappings.
+// It returns false if the type is not mapped.
+func (opts *ConvertOptions) mappedGqlType(t *types.Named) (gqlTypeIsCustScalar, bool) {
+	if len(opts.TypeMappings) == 0 || t.Obj().Pkg() == nil {
+		return gqlTypeIsCustScalar{}, false
+	}
+	pkg := t.Obj().Pkg()
+	for _, goType := range []string{pkg.Path() + "." + t.Obj().Name(), pkg.Name() + "." + t.Obj().Name()} {
+		if gqlType, ok := opts.TypeMappings[goType]; ok {
+			return gqlTypeIsCustScalar{gqlType: gqlType, isCustomScalar: !gqlBuiltinScalars[gqlType]}, true
+		}
+	}
+	return gqlTypeIsCustScalar{}, false
+}
+
+// intFitsGqlInt reports whether all values of an integer kind fit in a GraphQL Int (a signed 32‐bit integer).
+// int and uint are considered 64 bits wide.
+func intFitsGqlInt(kind types.BasicKind) bool {
+	switch kind {
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16, types.UntypedRune:
+		return true
+	}
+	return false
+}
+
+// integerGqlType returns the GraphQL type of an integer kind according to the integer policy.
+// It returns false if the kind is not an integer or no integer policy is set.
+func (opts *ConvertOptions) integerGqlType(kind types.BasicKind) (gqlTypeIsCustScalar, bool) {
+	if opts.IntPolicy == "" || types.Typ[kind].Info()&types.IsInteger == 0 {
+		return gqlTypeIsCustScalar{}, false
+	}
+	if intFitsGqlInt(kind) {
+		return gqlTypeIsCustScalar{gqlType: "Int"}, true
+	}
+	switch opts.IntPolicy {
+	case IntPolicyStrict:
+		return gqlTypeIsCustScalar{gqlType: "BigInt", isCustomScalar: true}, true
+	case IntPolicyLenient:
+		return gqlTypeIsCustScalar{gqlType: "Int"}, true
+	case IntPolicyString:
+		return gqlTypeIsCustScalar{gqlType: "String"}, true
+	default:
+		return gqlTypeIsCustScalar{gqlType: opts.IntPolicy, isCustomScalar: !gqlBuiltinScalars[opts.IntPolicy]}, true
+	}
+}
@@ -0,0 +1,120 @@
+package conversion
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+// TestIntegerGqlType is a unit test for the integer policies of ConvertOptions.
+func TestIntegerGqlType(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     string
+		goType     types.Type
+		wantType   string
+		wantScalar bool
+	}{
+		{name: "DefaultInt", policy: "", goType: types.Typ[types.Int], wantType: "Int"},
+		{name: "DefaultInt64", policy: "", goType: types.Typ[types.Int64], wantType: "BigInt", wantScalar: true},
+		{name: "StrictInt", policy: IntPolicyStrict, goType: types.Typ[types.Int], wantType: "BigInt", wantScalar: true},
+		{name: "StrictUint32", policy: IntPolicyStrict, goType: types.Typ[types.Uint32], wantType: "BigInt", wantScalar: true},
+		{name: "StrictInt32", policy: IntPolicyStrict, goType: types.Typ[types.Int32], wantType: "Int"},
+		{name: "StrictUntypedInt", policy: IntPolicyStrict, goType: types.Typ[types.UntypedInt], wantType: "BigInt", wantScalar: true},
+		{name: "LenientInt64", policy: IntPolicyLenient, goType: types.Typ[types.Int64], wantType: "Int"},
+		{name: "LenientUntypedInt", policy: IntPolicyLenient, goType: types.Typ[types.UntypedInt], wantType: "Int"},
+		{name: "StringUint64", policy: IntPolicyString, goType: types.Typ[types.Uint64], wantType: "String"},
+		{name: "StringUint16", policy: IntPolicyString, goType: types.Typ[types.Uint16], wantType: "Int"},
+		{name: "NamedScalarInt", policy: "Long", goType: types.Typ[types.Int], wantType: "Long", wantScalar: true},
+		// A built-in scalar is used without declaring a custom scalar
+		{name: "BuiltinScalarInt", policy: "ID", goType: types.Typ[types.Int], wantType: "ID"},
+		{name: "BuiltinScalarFloat", policy: "Float", goType: types.Typ[types.Uint64], wantType: "Float"},
+		{name: "PolicyIgnoresFloat", policy: IntPolicyString, goType: types.Typ[types.Float64], wantType: "Float"},
+		{
+			name:   "NamedIntegerDefault",
+			policy: "",
+			goType: types.NewNamed(types.NewTypeName(token.NoPos, nil, "Count", nil), types.Typ[types.Int64], nil),
+			// kept as a custom scalar named after the type
+			wantType:   "Count",
+			wantScalar: true,
+		},
+		{
+			name:     "NamedIntegerLenient",
+			policy:   IntPolicyLenient,
+			goType:   types.NewNamed(types.NewTypeName(token.NoPos, nil, "Count", nil), types.Typ[types.Int64], nil),
+			wantType: "Int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got GqlFieldsDefinition
+			err := newConverter(&ConvertOptions{IntPolicy: tt.policy}).convertType(tt.goType, &got)
+			if err != nil {
+				t.Fatalf("convertType() error = %v", err)
+			}
+			if got.GqlFieldType != tt.wantType || got.IsCustomScalar != tt.wantScalar {
+				t.Errorf("convertType() = %s (scalar %v), want %s (scalar %v)", got.GqlFieldType, got.IsCustomScalar, tt.wantType, tt.wantScalar)
+			}
+		})
+	}
+}
+
+// TestConvertOptionsValidate is a unit test for the validation of ConvertOptions.
+func TestConvertOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ConvertOptions
+		wantErr bool
+	}{
+		{name: "Empty", opts: ConvertOptions{}},
+		{name: "Strict", opts: ConvertOptions{IntPolicy: IntPolicyStrict}},
+		{name: "ScalarName", opts: ConvertOptions{IntPolicy: "Int64"}},
+		{name: "InvalidScalarName", opts: ConvertOptions{IntPolicy: "big-int"}, wantErr: true},
+		{name: "TypeMapping", opts: ConvertOptions{TypeMappings: map[string]string{"time.Time": "DateTime"}}},
+		{name: "InvalidTypeMapping", opts: ConvertOptions{TypeMappings: map[string]string{"time.Time": "Date Time"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestTypeMappings is a unit test for the type mappings of ConvertOptions.
+func TestTypeMappings(t *testing.T) {
+	timePkg := types.NewPackage("time", "time")
+	modelsPkg := types.NewPackage("github.com/acme/models", "models")
+	timeType := types.NewNamed(types.NewTypeName(token.NoPos, timePkg, "Time", nil), types.NewStruct(nil, nil), nil)
+	statusType := types.NewNamed(types.NewTypeName(token.NoPos, modelsPkg, "Status", nil), types.Typ[types.Int], nil)
+
+	tests := []struct {
+		name       string
+		mappings   map[string]string
+		goType     types.Type
+		wantType   string
+		wantScalar bool
+	}{
+		{name: "NotMapped", mappings: map[string]string{"time.Duration": "Int"}, goType: timeType, wantType: "Time"},
+		{name: "PackageName", mappings: map[string]string{"time.Time": "DateTime"}, goType: timeType, wantType: "DateTime", wantScalar: true},
+		{name: "ImportPath", mappings: map[string]string{"github.com/acme/models.Status": "String"}, goType: statusType, wantType: "String"},
+		{name: "Pointer", mappings: map[string]string{"time.Time": "DateTime"}, goType: types.NewPointer(timeType), wantType: "DateTime", wantScalar: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got GqlFieldsDefinition
+			err := newConverter(&ConvertOptions{TypeMappings: tt.mappings}).convertType(tt.goType, &got)
+			if err != nil {
+				t.Fatalf("convertType() error = %v", err)
+			}
+			if got.GqlFieldType != tt.wantType || got.IsCustomScalar != tt.wantScalar {
+				t.Errorf("convertType() = %s (scalar %v), want %s (scalar %v)", got.GqlFieldType, got.IsCustomScalar, tt.wantType, tt.wantScalar)
+			}
+		})
+	}
+}
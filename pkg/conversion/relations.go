@@ -0,0 +1,105 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policies for the relations inferred from the foreign key fields
+const (
+	// RelationsAdd adds the relation next to the foreign key field, e.g. author: Author next to author_id: Int
+	RelationsAdd = "add"
+	// RelationsReplace replaces the foreign key field by the relation
+	RelationsReplace = "replace"
+)
+
+// validateRelationsPolicy returns an error if the relations policy is not valid.
+func validateRelationsPolicy(policy string) error {
+	switch policy {
+	case "", RelationsAdd, RelationsReplace:
+		return nil
+	}
+	return fmt.Errorf("%v: relations policy %q is neither %s nor %s", InvalidOptionErr, policy, RelationsAdd, RelationsReplace)
+}
+
+// applyRelations infers the relations of the object types from their foreign key fields: a field whose Go field is
+// named after an object type X followed by ID or Id, e.g. AuthorID, gets a relation field of type X, named like the
+// field without its id suffix, e.g. author for author_id, added next to it or replacing it depending on the policy.
+// The relation has no Go counterpart and is meant to be resolved from the foreign key. A foreign key whose relation
+// name is empty or already used by another field of the type is left as is.
+func (r *schemaResolver) applyRelations() {
+	objects := make(map[string]bool)
+	for _, schemaType := range r.types {
+		if !schemaType.IsInput() && !schemaType.Extend {
+			objects[schemaType.Name] = true
+		}
+	}
+
+	for idx := range r.types {
+		schemaType := &r.types[idx]
+		if schemaType.IsInput() || schemaType.Extend {
+			continue
+		}
+		names := make(map[string]bool, len(schemaType.Fields))
+		for _, field := range schemaType.Fields {
+			names[field.Name] = true
+		}
+		var fields []GqlSchemaField
+		for _, field := range schemaType.Fields {
+			object, ok := foreignKeyObject(field.GoName)
+			name := relationName(field.Name)
+			if !ok || !objects[object] || len(field.Arguments) != 0 || name == "" || names[name] {
+				fields = append(fields, field)
+				continue
+			}
+			names[name] = true
+			if r.opts.Relations == RelationsAdd {
+				fields = append(fields, field)
+			}
+			fields = append(fields, GqlSchemaField{Name: name, Type: object, NonNull: field.NonNull, ForeignKey: field.GoName, Position: field.Position})
+		}
+		schemaType.Fields = fields
+	}
+}
+
+// foreignKeyObject returns the object type a Go field name refers to, e.g. Author for AuthorID or AuthorId, and true,
+// or false if the name does not end with ID or Id.
+func foreignKeyObject(goName string) (string, bool) {
+	for _, suffix := range []string{"ID", "Id"} {
+		if object, ok := strings.CutSuffix(goName, suffix); ok && object != "" {
+			return object, true
+		}
+	}
+	return "", false
+}
+
+// relationName returns the name of the relation of a foreign key field, its name without the id suffix, ignoring case,
+// and the underscores preceding it, e.g. author for author_id or authorId.
+func relationName(foreignKey string) string {
+	if len(foreignKey) < 2 || !strings.EqualFold(foreignKey[len(foreignKey)-2:], "id") {
+		return ""
+	}
+	return strings.TrimRight(foreignKey[:len(foreignKey)-2], "_")
+}
+
+// RelationsReport returns an info Diagnostic for each relation of the GqlSchema inferred from a foreign key field, e.g.
+// to list the resolvers to write.
+func RelationsReport(schema *GqlSchema) Diagnostics {
+	var diagnostics Diagnostics
+	for _, schemaType := range schema.Types {
+		for _, field := range schemaType.Fields {
+			if field.ForeignKey == "" {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity:   SeverityInfo,
+				Code:       CodeRelation,
+				Position:   schemaType.fieldPosition(field.Name),
+				StructName: schemaType.Name,
+				FieldName:  field.Name,
+				Message:    fmt.Sprintf("relation to %s inferred from the Go field %s, it needs a resolver", field.Type, field.ForeignKey),
+			})
+		}
+	}
+	return diagnostics
+}
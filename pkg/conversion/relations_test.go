@@ -0,0 +1,103 @@
+package conversion
+
+import "testing"
+
+// TestRelations is a unit test for the relations inferred from the foreign keys of ResolveGqlSchema.
+func TestRelations(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "User", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Email", GqlFieldType: "String", GqlFieldTags: `json:"email"`}}},
+		{GqlTypeName: "Order", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "ID", GqlFieldType: "Int", GqlFieldTags: `json:"id"`},
+			{GqlFieldName: "UserID", GqlFieldType: "Int", GqlFieldTags: `json:"user_id" validate:"required"`},
+			{GqlFieldName: "PaymentID", GqlFieldType: "Int", GqlFieldTags: `json:"payment_id"`},
+		}},
+	}
+	user := "type User {\n  email: String\n}\n\n"
+	tests := []struct {
+		name    string
+		policy  string
+		defs    []GqlTypeDefinition
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "None",
+			want: "\ntype Order {\n  id: Int\n  user_id: Int!\n  payment_id: Int\n}\n\n" + user,
+		},
+		{
+			// PaymentID does not reference an object type
+			name:   "Add",
+			policy: RelationsAdd,
+			want:   "\ntype Order {\n  id: Int\n  user_id: Int!\n  user: User!\n  payment_id: Int\n}\n\n" + user,
+		},
+		{
+			name:   "Replace",
+			policy: RelationsReplace,
+			want:   "\ntype Order {\n  id: Int\n  user: User!\n  payment_id: Int\n}\n\n" + user,
+		},
+		{
+			// The relation would have the name of an existing field
+			name:   "NameUsed",
+			policy: RelationsReplace,
+			defs: []GqlTypeDefinition{
+				{GqlTypeName: "User", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Email", GqlFieldType: "String"}}},
+				{GqlTypeName: "Order", GqlFields: []GqlFieldsDefinition{
+					{GqlFieldName: "User", GqlFieldType: "User"},
+					{GqlFieldName: "UserID", GqlFieldType: "Int"},
+				}},
+			},
+			want: "\ntype Order {\n  User: User\n  UserID: Int\n}\n\ntype User {\n  Email: String\n}\n\n",
+		},
+		{name: "InvalidPolicy", policy: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs := gqlTypeDefs
+			if tt.defs != nil {
+				defs = tt.defs
+			}
+			opts := &PrettyPrintOptions{UseJsonTags: true, RequireTags: SpecTagRequire{Key: "validate", Val: "required"}, Relations: tt.policy}
+			schema, err := ResolveGqlSchema(defs, opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveGqlSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && schema.String() != tt.want {
+				t.Errorf("ResolveGqlSchema() = %q, want %q", schema.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestRelationsReport is a unit test for the RelationsReport function.
+func TestRelationsReport(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{GqlTypeName: "User", GqlTypePackage: "github.com/acme/models", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "Email", GqlFieldType: "String"}}},
+		{GqlTypeName: "Order", GqlTypePackage: "github.com/acme/models", GqlFields: []GqlFieldsDefinition{
+			{GqlFieldName: "UserID", GqlFieldType: "Int", GqlFieldTags: `json:"user_id"`},
+		}},
+	}
+	schema, err := ResolveGqlSchema(gqlTypeDefs, &PrettyPrintOptions{UseJsonTags: true, Relations: RelationsAdd, GoModelDirectives: true, Order: OrderSource})
+	if err != nil {
+		t.Fatalf("ResolveGqlSchema() error = %v", err)
+	}
+	// The relation is resolved rather than bound to a Go field
+	if got, want := schema.Types[1].Fields[1].String(), "  user: User @goField(forceResolver: true)\n"; got != want {
+		t.Errorf("ResolveGqlSchema() field = %q, want %q", got, want)
+	}
+	diagnostics := RelationsReport(schema)
+	want := "Order.user: relation to User inferred from the Go field UserID, it needs a resolver [relation]"
+	if len(diagnostics) != 1 || diagnostics[0].Severity != SeverityInfo || diagnostics[0].String() != "info: "+want {
+		t.Errorf("RelationsReport() = %v, want %q", diagnostics, want)
+	}
+}
+
+// TestRelationName is a unit test for the relationName function.
+func TestRelationName(t *testing.T) {
+	tests := map[string]string{"author_id": "author", "authorId": "author", "AuthorID": "Author", "id": "", "d": "", "author": ""}
+	for foreignKey, want := range tests {
+		if got := relationName(foreignKey); got != want {
+			t.Errorf("relationName(%s) = %q, want %q", foreignKey, got, want)
+		}
+	}
+}
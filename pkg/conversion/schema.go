@@ -0,0 +1,445 @@
+package conversion
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// GqlSchema represents the GraphQL schema resolved from a slice of GqlTypeDefinition, as it is printed.
+type GqlSchema struct {
+	// SchemaDirectives are the directives of the schema itself, printed as extend schema, e.g. the @link of a federated schema
+	SchemaDirectives []GqlSchemaDirective
+	Directives       []GqlSchemaDirectiveDefinition // Directives are the definitions of the directives the types use
+	Scalars          []GqlSchemaScalar              // Scalars are the custom scalars the types need to be defined
+	Types            []GqlSchemaType                // Types are the GraphQL types, including the nested custom types
+}
+
+// GqlSchemaDirectiveDefinition represents the definition of a directive used by a GqlSchema.
+type GqlSchemaDirectiveDefinition struct {
+	Name       string // Name is the name of the directive, without the @
+	Definition string // Definition is the SDL of the definition, e.g. directive @goModel(model: String) on OBJECT
+}
+
+// GqlSchemaScalar represents a custom scalar of a GqlSchema.
+type GqlSchemaScalar struct {
+	Name      string     // Name is the name of the scalar
+	GoModel   string     // GoModel is the Go named type converted into the scalar qualified by its import path, empty if it is not a named type, e.g. for an int64
+	GoType    types.Type `json:"-"` // GoType is the Go type converted into the scalar, nil if unknown, e.g. for a scalar read from a schema file
+	Marshaler string     // Marshaler is the marshaler interface implemented by GoType that decided the scalar, if any
+}
+
+// GqlSchemaType represents a GraphQL type of a GqlSchema.
+type GqlSchemaType struct {
+	Name     string           // Name is the name of the GraphQL type
+	Kind     string           // Kind is the kind of the GraphQL type, see KindObject and KindInput. When empty, it is an object type
+	Fields   []GqlSchemaField // Fields are the fields of the type, embedded fields flattened and ignored fields left out
+	Position token.Position   // Position is the position of the struct declaration in the Go source, if known
+	Package  string           // Package is the import path of the Go package declaring the struct, or the struct needing a nested type
+	GoModel  string           // GoModel is the Go struct of the type qualified by its import path, empty if it is not declared in Go, e.g. for a map
+	GoType   types.Type       `json:"-"` // GoType is the Go named type of the struct, nil if unknown, e.g. for a map or a type read from a schema file
+	Entity   bool             // Entity is true if the struct is marked with EntityDocDirective
+	Extend   bool             // Extend is true if the type is an extension of a type defined elsewhere, e.g. extend type Query
+	// Directives are the directives applied to the type
+	Directives []GqlSchemaDirective
+}
+
+// GqlSchemaField represents a field of a GqlSchemaType.
+type GqlSchemaField struct {
+	Name    string // Name is the name of the field, from the tag to use if any
+	Type    string // Type is the GraphQL type of the field, without the non-null mark
+	NonNull bool   // NonNull is true if the field is required
+	GoName  string // GoName is the name of the Go struct field, empty if the field has no Go counterpart
+	// Position is the position of the Go struct field declaration, or of the foreign key of a relation, if known
+	Position token.Position
+	// ForeignKey is the name of the Go struct field the relation is inferred from, e.g. AuthorID, empty if the field is
+	// not an inferred relation
+	ForeignKey string
+	// Arguments are the arguments of the field, e.g. the pagination arguments of a connection
+	Arguments []GqlSchemaArgument
+	// Directives are the directives applied to the field
+	Directives []GqlSchemaDirective
+}
+
+// GqlSchemaArgument represents an argument of a GqlSchemaField.
+type GqlSchemaArgument struct {
+	Name         string // Name is the name of the argument
+	Type         string // Type is the GraphQL input type of the argument, e.g. Int or String!
+	DefaultValue string // DefaultValue is the default value of the argument as written, e.g. 10, empty if it has none
+}
+
+// GqlSchemaDirective represents a directive applied to a type or a field of a GqlSchema.
+type GqlSchemaDirective struct {
+	Name      string                  // Name is the name of the directive, without the @
+	Arguments []GqlSchemaDirectiveArg // Arguments are the arguments of the directive, in the order they are printed
+}
+
+// GqlSchemaDirectiveArg represents an argument of a GqlSchemaDirective.
+type GqlSchemaDirectiveArg struct {
+	Name  string // Name is the name of the argument
+	Value string // Value is the GraphQL literal of the value, e.g. "Article" quoted or true
+}
+
+// Kinds of the GraphQL types of a GqlSchema
+const (
+	// KindObject is the kind of an object type, an output type
+	KindObject = "type"
+	// KindInput is the kind of an input object type
+	KindInput = "input"
+)
+
+// IsInput returns true if the type is an input object type.
+func (t GqlSchemaType) IsInput() bool {
+	return t.Kind == KindInput
+}
+
+// Orders of the types and scalars of a GqlSchema
+const (
+	// OrderAlpha sorts the types and the scalars by name
+	OrderAlpha = "alpha"
+	// OrderSource keeps the types in the order the structs are declared in the Go source, each followed by its nested
+	// custom types, and the scalars in the order they are first used
+	OrderSource = "source"
+	// OrderTopo sorts the types so that the types a type depends on come before it, and the scalars in the order
+	// they are first used
+	OrderTopo = "topo"
+)
+
+// ResolveGqlSchema takes a slice of GqlTypeDefinition and PrettyPrintOptions and returns the GqlSchema to print.
+// It names the fields from the tag to use, leaves out the ignored fields, marks the required ones, flattens the
+// embedded fields and the nested custom types, applies the custom directives of the tags and doc comments, applies the
+// empty types policy, converts the identifiers into IDs, replaces the lists by Relay connections, adds the operations
+// of the entities, infers the relations from the foreign keys, adds the federation directives and the gqlgen
+// directives, adds the definitions of the custom directives used and orders the types, scalars and fields according
+// to the options.
+func ResolveGqlSchema(gqlTypeDefs []GqlTypeDefinition, opts *PrettyPrintOptions) (*GqlSchema, error) {
+	if err := opts.validatePolicies(); err != nil {
+		return nil, err
+	}
+	directiveDefs, err := directiveDefinitions(opts.DirectiveDefinitions)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Order == OrderSource || opts.Order == OrderTopo {
+		gqlTypeDefs = append([]GqlTypeDefinition(nil), gqlTypeDefs...)
+		sort.SliceStable(gqlTypeDefs, func(i, j int) bool {
+			return positionBefore(gqlTypeDefs[i].GqlTypePosition, gqlTypeDefs[j].GqlTypePosition)
+		})
+	}
+
+	r := schemaResolver{
+		opts:             opts,
+		tag:              opts.tagToUse(),
+		tagValueToIgnore: opts.tagFieldsValueToIgnore(),
+		scalars:          make(map[string]bool),
+		scalarDefs:       make(map[string]GqlSchemaScalar),
+		typesSeen:        make(map[string]bool),
+		connectionFields: make(map[string]bool),
+		connected:        make(map[string]bool),
+		idFields:         make(map[string]bool),
+		federationFields: make(map[string][]string),
+		docDirectives:    make(map[string][]string),
+	}
+	for _, gqlTypeDef := range gqlTypeDefs {
+		if err := r.resolveType(gqlTypeDef, false, ""); err != nil {
+			return nil, err
+		}
+	}
+	r.applyEmptyPolicy()
+	if err := r.applyIDRules(); err != nil {
+		return nil, err
+	}
+	if err := r.applyConnections(); err != nil {
+		return nil, err
+	}
+	if opts.Operations {
+		if err := r.applyOperations(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Relations != "" {
+		r.applyRelations()
+	}
+	if opts.Federation {
+		if err := r.applyFederation(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.GoModelDirectives {
+		r.applyGoModelDirectives()
+	}
+	r.addDirectiveDefinitions(directiveDefs)
+
+	schema := &GqlSchema{SchemaDirectives: r.schemaDirectives, Directives: r.directives, Types: r.types}
+	switch opts.Order {
+	case OrderTopo:
+		schema.Types = sortTypesTopo(schema.Types)
+	case OrderSource:
+	default:
+		sort.SliceStable(schema.Types, func(i, j int) bool { return schema.Types[i].Name < schema.Types[j].Name })
+	}
+	if opts.SortFields {
+		for _, schemaType := range schema.Types {
+			sort.SliceStable(schemaType.Fields, func(i, j int) bool { return schemaType.Fields[i].Name < schemaType.Fields[j].Name })
+		}
+	}
+	schema.Scalars = orderScalars(schema.Types, r.scalars, opts.Order)
+	for idx, scalar := range schema.Scalars {
+		if scalarDef, ok := r.scalarDefs[scalar.Name]; ok {
+			schema.Scalars[idx] = scalarDef
+		}
+	}
+	return schema, nil
+}
+
+// schemaResolver accumulates the types and scalars of a GqlSchema while resolving the GqlTypeDefinition.
+type schemaResolver struct {
+	opts             *PrettyPrintOptions
+	tag              string
+	tagValueToIgnore string
+	types            []GqlSchemaType
+	scalars          map[string]bool
+	scalarDefs       map[string]GqlSchemaScalar // scalarDefs are the scalars with their Go type, the first one found when several share a name
+	directives       []GqlSchemaDirectiveDefinition
+	typesSeen        map[string]bool
+	connectionFields map[string]bool     // connectionFields are the fields tagged with ConnectionTagValue, as Type.field
+	connected        map[string]bool     // connected are the object types whose connection types are added
+	idFields         map[string]bool     // idFields are the fields tagged with the IDType, as Type.field
+	federationFields map[string][]string // federationFields are the federation values of the GqlTag of the fields, as Type.field
+	docDirectives    map[string][]string // docDirectives are the doc directives of the structs, by type name
+	schemaDirectives []GqlSchemaDirective
+}
+
+// resolveType appends the GqlSchemaType of a GqlTypeDefinition, followed by its nested custom types.
+// A nested custom type gets the package of the type needing it, parentPackage, and is only resolved the first time
+// it is found, e.g. when its struct is embedded several times.
+func (r *schemaResolver) resolveType(gqlTypeDef GqlTypeDefinition, nested bool, parentPackage string) error {
+	if nested {
+		if r.typesSeen[gqlTypeDef.GqlTypeName] {
+			return nil
+		}
+		r.typesSeen[gqlTypeDef.GqlTypeName] = true
+	}
+	schemaType := GqlSchemaType{Name: gqlTypeDef.GqlTypeName, Position: gqlTypeDef.GqlTypePosition, Package: gqlTypeDef.GqlTypePackage}
+	for _, directive := range gqlTypeDef.GqlTypeDirectives {
+		schemaType.Entity = schemaType.Entity || directive == EntityDocDirective
+	}
+	r.docDirectives[schemaType.Name] = gqlTypeDef.GqlTypeDirectives
+	directives, err := typeDirectives(schemaType.Name, gqlTypeDef.GqlTypeDirectives)
+	if err != nil {
+		return err
+	}
+	schemaType.Directives = directives
+	if schemaType.Package == "" {
+		schemaType.Package = parentPackage
+	} else {
+		// The types built from a map have no package, there is no Go struct to bind
+		schemaType.GoModel = gqlTypeDef.GqlTypePackage + "." + gqlTypeDef.GqlTypeName
+		schemaType.GoType = gqlTypeDef.GqlTypeGoType
+	}
+	var nestedTypes []GqlTypeDefinition
+	fields, err := r.resolveFields(schemaType.Name, gqlTypeDef.GqlFields, &nestedTypes)
+	if err != nil {
+		return err
+	}
+	schemaType.Fields = fields
+	r.types = append(r.types, schemaType)
+	for _, nestedType := range nestedTypes {
+		if err := r.resolveType(nestedType, true, schemaType.Package); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveFields returns the GqlSchemaField of a slice of GqlFieldsDefinition of the type typeName, flattening the
+// embedded fields, and appends their nested custom types to nestedTypes.
+func (r *schemaResolver) resolveFields(typeName string, fields []GqlFieldsDefinition, nestedTypes *[]GqlTypeDefinition) ([]GqlSchemaField, error) {
+	var schemaFields []GqlSchemaField
+	for _, field := range fields {
+		tags, err := parseFieldTags(field)
+		if err != nil {
+			return nil, err
+		}
+
+		fieldName, err := updateFieldName(field.GqlFieldName, tags, r.tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if fieldName == r.tagValueToIgnore {
+			continue
+		}
+
+		requiredFieldmark, err := updateRequiredFieldMark(tags, &r.opts.RequireTags, "")
+		if err != nil {
+			return nil, err
+		}
+
+		*nestedTypes = append(*nestedTypes, field.NestedCustomType...)
+		if field.GqlFieldIsEmbedded {
+			embeddedFields, err := r.resolveFields(typeName, field.GqlGenFieldsEmbedded, nestedTypes)
+			if err != nil {
+				return nil, err
+			}
+			schemaFields = append(schemaFields, embeddedFields...)
+			continue
+		}
+
+		if field.IsCustomScalar && !isJsonStringField(field) {
+			scalarName := namedGqlType(field.GqlFieldType)
+			r.scalars[scalarName] = true
+			if _, ok := r.scalarDefs[scalarName]; !ok {
+				r.scalarDefs[scalarName] = GqlSchemaScalar{
+					Name:      scalarName,
+					GoModel:   field.GqlFieldGoType,
+					GoType:    field.GqlFieldScalarGoType,
+					Marshaler: field.GqlFieldMarshaler,
+				}
+			}
+		}
+		if hasGqlTagValue(tags, ConnectionTagValue) {
+			r.connectionFields[typeName+"."+fieldName] = true
+		}
+		tagged, err := idTagged(tags, typeName, fieldName)
+		if err != nil {
+			return nil, err
+		}
+		if tagged {
+			r.idFields[typeName+"."+fieldName] = true
+		}
+		for _, value := range federationTagValues {
+			if hasGqlTagValue(tags, value) {
+				r.federationFields[typeName+"."+fieldName] = append(r.federationFields[typeName+"."+fieldName], value)
+			}
+		}
+		directives, err := fieldDirectives(tags, typeName, fieldName)
+		if err != nil {
+			return nil, err
+		}
+		schemaFields = append(schemaFields, GqlSchemaField{
+			Name:       fieldName,
+			Type:       fieldOutputType(field),
+			NonNull:    requiredFieldmark != "",
+			GoName:     field.GqlFieldName,
+			Position:   field.GqlFieldPosition,
+			Directives: directives,
+		})
+	}
+	return schemaFields, nil
+}
+
+// fieldPosition returns the position of the field of the type named field, or the position of the type if the field
+// is empty or its position is not known, e.g. for a generated field.
+func (t GqlSchemaType) fieldPosition(field string) token.Position {
+	for _, schemaField := range t.Fields {
+		if field != "" && schemaField.Name == field && schemaField.Position.IsValid() {
+			return schemaField.Position
+		}
+	}
+	return t.Position
+}
+
+// positionBefore reports whether the position a comes before the position b, by file name then offset.
+func positionBefore(a token.Position, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	return a.Offset < b.Offset
+}
+
+// Validate returns an InvalidOptionErr error if the order, the empty types policy, the connections policy, the
+// relations policy or a directive definition is not valid.
+func (opts *PrettyPrintOptions) Validate() error {
+	if err := opts.validatePolicies(); err != nil {
+		return err
+	}
+	_, err := directiveDefinitions(opts.DirectiveDefinitions)
+	return err
+}
+
+// validatePolicies returns an InvalidOptionErr error if the order, the empty types policy, the connections policy or
+// the relations policy is not valid.
+func (opts *PrettyPrintOptions) validatePolicies() error {
+	switch opts.Order {
+	case "", OrderAlpha, OrderSource, OrderTopo:
+	default:
+		return fmt.Errorf("%v: order %q is neither %s, %s nor %s", InvalidOptionErr, opts.Order, OrderAlpha, OrderSource, OrderTopo)
+	}
+	if err := validateEmptyPolicy(opts.EmptyPolicy); err != nil {
+		return err
+	}
+	if err := validateConnectionsPolicy(opts.Connections); err != nil {
+		return err
+	}
+	return validateRelationsPolicy(opts.Relations)
+}
+
+// orderScalars returns all the scalars ordered according to the order of the types: sorted by name, or in the order
+// they are first used by the fields and arguments of the types for OrderSource and OrderTopo, followed by the ones
+// no type uses sorted by name.
+func orderScalars(schemaTypes []GqlSchemaType, scalars map[string]bool, order string) []GqlSchemaScalar {
+	var names []string
+	seen := make(map[string]bool, len(scalars))
+	use := func(gqlType string) {
+		if name := namedGqlType(gqlType); scalars[name] && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if order == OrderSource || order == OrderTopo {
+		for _, schemaType := range schemaTypes {
+			for _, field := range schemaType.Fields {
+				use(field.Type)
+				for _, argument := range field.Arguments {
+					use(argument.Type)
+				}
+			}
+		}
+	}
+	var unused []string
+	for name := range scalars {
+		if !seen[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	names = append(names, unused...)
+	schemaScalars := make([]GqlSchemaScalar, len(names))
+	for i, name := range names {
+		schemaScalars[i] = GqlSchemaScalar{Name: name}
+	}
+	return schemaScalars
+}
+
+// sortTypesTopo returns the types sorted so that the types referenced by the fields of a type come before it.
+// Types are otherwise kept in their order, and a cycle is broken where it is first found.
+func sortTypesTopo(schemaTypes []GqlSchemaType) []GqlSchemaType {
+	byName := make(map[string]int, len(schemaTypes))
+	for i, schemaType := range schemaTypes {
+		if _, ok := byName[schemaType.Name]; !ok {
+			byName[schemaType.Name] = i
+		}
+	}
+	visited := make([]bool, len(schemaTypes))
+	sorted := make([]GqlSchemaType, 0, len(schemaTypes))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, field := range schemaTypes[i].Fields {
+			if dependency, ok := byName[namedGqlType(field.Type)]; ok {
+				visit(dependency)
+			}
+		}
+		sorted = append(sorted, schemaTypes[i])
+	}
+	for i := range schemaTypes {
+		visit(i)
+	}
+	return sorted
+}
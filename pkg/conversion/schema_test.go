@@ -0,0 +1,122 @@
+package conversion
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// TestResolveGqlSchemaOrder is a unit test for the ordering of the types, scalars and fields of ResolveGqlSchema.
+func TestResolveGqlSchemaOrder(t *testing.T) {
+	gqlTypeDefs := []GqlTypeDefinition{
+		{
+			GqlTypeName:     "Comment",
+			GqlTypePosition: token.Position{Filename: "models.go", Offset: 200},
+			GqlFields: []GqlFieldsDefinition{
+				{GqlFieldName: "Status", GqlFieldType: "Status", IsCustomScalar: true},
+				{GqlFieldName: "Author", GqlFieldType: "User"},
+			},
+		},
+		{
+			GqlTypeName:     "Article",
+			GqlTypePosition: token.Position{Filename: "models.go", Offset: 100},
+			GqlFields: []GqlFieldsDefinition{
+				{GqlFieldName: "Views", GqlFieldType: "BigInt", IsCustomScalar: true},
+				{GqlFieldName: "Comments", GqlFieldType: "[Comment]"},
+				{GqlFieldName: "Attrs", GqlFieldType: "AttrsMap", NestedCustomType: []GqlTypeDefinition{
+					{GqlTypeName: "AttrsMap", GqlFields: []GqlFieldsDefinition{{GqlFieldName: "key", GqlFieldType: "String"}}},
+				}},
+			},
+		},
+		{
+			GqlTypeName:     "User",
+			GqlTypePosition: token.Position{Filename: "models.go", Offset: 300},
+			GqlFields: []GqlFieldsDefinition{
+				{GqlFieldName: "Name", GqlFieldType: "String"},
+				{GqlFieldName: "Age", GqlFieldType: "Int"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		opts        PrettyPrintOptions
+		wantTypes   string
+		wantScalars string
+		wantFields  string // fields of User
+		wantErr     bool
+	}{
+		{name: "Default", opts: PrettyPrintOptions{}, wantTypes: "Article,AttrsMap,Comment,User", wantScalars: "BigInt,Status", wantFields: "Name,Age"},
+		{name: "Alpha", opts: PrettyPrintOptions{Order: OrderAlpha, SortFields: true}, wantTypes: "Article,AttrsMap,Comment,User", wantScalars: "BigInt,Status", wantFields: "Age,Name"},
+		{name: "Source", opts: PrettyPrintOptions{Order: OrderSource}, wantTypes: "Article,AttrsMap,Comment,User", wantScalars: "BigInt,Status", wantFields: "Name,Age"},
+		{name: "Topo", opts: PrettyPrintOptions{Order: OrderTopo}, wantTypes: "User,Comment,AttrsMap,Article", wantScalars: "Status,BigInt", wantFields: "Name,Age"},
+		{name: "Invalid", opts: PrettyPrintOptions{Order: "random"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ResolveGqlSchema(gqlTypeDefs, &tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveGqlSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var typeNames, scalarNames, fieldNames []string
+			for _, schemaType := range schema.Types {
+				typeNames = append(typeNames, schemaType.Name)
+				if schemaType.Name == "User" {
+					for _, field := range schemaType.Fields {
+						fieldNames = append(fieldNames, field.Name)
+					}
+				}
+			}
+			for _, scalar := range schema.Scalars {
+				scalarNames = append(scalarNames, scalar.Name)
+			}
+			if got := strings.Join(typeNames, ","); got != tt.wantTypes {
+				t.Errorf("ResolveGqlSchema() types = %v, want %v", got, tt.wantTypes)
+			}
+			if got := strings.Join(scalarNames, ","); got != tt.wantScalars {
+				t.Errorf("ResolveGqlSchema() scalars = %v, want %v", got, tt.wantScalars)
+			}
+			if got := strings.Join(fieldNames, ","); got != tt.wantFields {
+				t.Errorf("ResolveGqlSchema() fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+// TestOrderScalars is a unit test for the orderScalars function, which orders the same scalars whatever the order.
+func TestOrderScalars(t *testing.T) {
+	schemaTypes := []GqlSchemaType{
+		{Name: "Comment", Fields: []GqlSchemaField{
+			{Name: "status", Type: "Status"},
+			{Name: "replies", Type: "[Comment]", Arguments: []GqlSchemaArgument{{Name: "after", Type: "Cursor"}}},
+		}},
+		{Name: "Article", Fields: []GqlSchemaField{{Name: "views", Type: "BigInt!"}, {Name: "status", Type: "Status"}}},
+	}
+	scalars := map[string]bool{"BigInt": true, "Cursor": true, "Status": true, "Unused": true, "Another": true}
+
+	tests := []struct {
+		order string
+		want  string
+	}{
+		{order: "", want: "Another,BigInt,Cursor,Status,Unused"},
+		{order: OrderAlpha, want: "Another,BigInt,Cursor,Status,Unused"},
+		{order: OrderSource, want: "Status,Cursor,BigInt,Another,Unused"},
+		{order: OrderTopo, want: "Status,Cursor,BigInt,Another,Unused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			var got []string
+			for _, scalar := range orderScalars(schemaTypes, scalars, tt.order) {
+				got = append(got, scalar.Name)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("orderScalars() = %v, want %v", strings.Join(got, ","), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package conversion
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// Policies for the fields of a type that GraphQL cannot represent
+const (
+	// UnsupportedPolicyError reports an InvalidTypeErr error Diagnostic, failing the conversion
+	UnsupportedPolicyError = "error"
+	// UnsupportedPolicySkip leaves the field out of the GraphQL type and reports a warning Diagnostic
+	UnsupportedPolicySkip = "skip"
+	// UnsupportedPolicyScalar converts the field into a custom scalar and reports a warning Diagnostic
+	UnsupportedPolicyScalar = "scalar"
+)
+
+// errSkipField is returned while converting a field that must be left out of its GraphQL type.
+var errSkipField = ConvertCustomError("field skipped")
+
+// isUnsupportedBasic reports whether t is a basic type GraphQL cannot represent, i.e. unsafe.Pointer or a complex number.
+func isUnsupportedBasic(t *types.Basic) bool {
+	return t.Kind() == types.UnsafePointer || t.Info()&types.IsComplex != 0
+}
+
+// convertUnsupportedType converts a type GraphQL cannot represent according to the unsupported types policy.
+// scalarName is the name of the custom scalar used with UnsupportedPolicyScalar.
+func (c *converter) convertUnsupportedType(t types.Type, scalarName string, gqlFieldDef *GqlFieldsDefinition) error {
+	switch c.opts.UnsupportedPolicy {
+	case UnsupportedPolicySkip:
+		c.diagnose(SeverityWarning, CodeUnsupportedType, fmt.Sprintf("unsupported type %s, field skipped", t))
+		return errSkipField
+	case UnsupportedPolicyScalar:
+		c.diagnose(SeverityWarning, CodeUnsupportedType, fmt.Sprintf("unsupported type %s, converted into scalar %s", t, scalarName))
+		gqlFieldDef.GqlFieldType = scalarName
+		gqlFieldDef.IsCustomScalar = true
+		return nil
+	default:
+		return fmt.Errorf("%s: %v", InvalidTypeErr, t.String())
+	}
+}
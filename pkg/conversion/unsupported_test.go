@@ -0,0 +1,64 @@
+package conversion
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/load"
+)
+
+// TestUnsupportedPolicy is a unit test for the policies applied to fields of an unsupported type.
+func TestUnsupportedPolicy(t *testing.T) {
+	pkg := types.NewPackage("some.pkg/path", "path")
+	fields := []*types.Var{
+		types.NewVar(token.NoPos, pkg, "Name", types.Typ[types.String]),
+		types.NewVar(token.NoPos, pkg, "Callback", types.NewSignatureType(nil, nil, nil, nil, nil, false)),
+		types.NewVar(token.NoPos, pkg, "Events", types.NewSlice(types.NewChan(types.SendRecv, types.Typ[types.Bool]))),
+		types.NewVar(token.NoPos, pkg, "Z", types.Typ[types.Complex128]),
+	}
+	structDef := load.StructDiscovered{
+		Name: types.NewTypeName(token.NoPos, pkg, "Legacy", nil),
+		Obj:  types.NewStruct(fields, nil),
+	}
+
+	tests := []struct {
+		name         string
+		policy       string
+		wantErr      bool
+		wantFields   []string
+		wantWarnings int
+	}{
+		{name: "Default", policy: "", wantErr: true},
+		{name: "Error", policy: UnsupportedPolicyError, wantErr: true},
+		{name: "Skip", policy: UnsupportedPolicySkip, wantFields: []string{"String"}, wantWarnings: 3},
+		{name: "Scalar", policy: UnsupportedPolicyScalar, wantFields: []string{"String", "Func", "[Chan]", "ComplexNumber"}, wantWarnings: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := BuildGqlTypesWithOptions([]load.StructDiscovered{structDef}, &ConvertOptions{UnsupportedPolicy: tt.policy})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildGqlTypesWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("BuildGqlTypesWithOptions() warnings = %v, want %d warnings", warnings, tt.wantWarnings)
+			}
+			var gotFields []string
+			for _, field := range got[0].GqlFields {
+				gotFields = append(gotFields, field.GqlFieldType)
+			}
+			if len(gotFields) != len(tt.wantFields) {
+				t.Fatalf("BuildGqlTypesWithOptions() fields = %v, want %v", gotFields, tt.wantFields)
+			}
+			for i := range gotFields {
+				if gotFields[i] != tt.wantFields[i] {
+					t.Errorf("BuildGqlTypesWithOptions() fields = %v, want %v", gotFields, tt.wantFields)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidateGqlSchema validates the GqlSchema against the GraphQL specification and returns the violations found:
+// - every type, scalar and field has a valid name, not reserved by introspection, and types and scalars are capitalized
+// - every type has at least one field
+// - every field, and every argument of the fields and of the directive definitions, references a built-in scalar, or
+// a scalar or type of the schema
+// - the names of the types and scalars, and of the fields of each type, are unique
+// - the fields of an object type reference output types, and the fields of an input type and the arguments reference
+// input types
+// - every type extension, e.g. extend type Query, extends a type of the same kind
+// - the directives applied to the types and fields are defined, and used according to their definition
+//
+// The violations are warnings, or errors when strict is true. They are positioned at the Go struct of the type, if known.
+func ValidateGqlSchema(schema *GqlSchema, strict bool) Diagnostics {
+	return ValidateGqlSchemaWithDefinitions(schema, strict, nil)
+}
+
+// ExternalDefinitions represents the definitions of the schema document the GqlSchema is merged into, e.g. the
+// hand-written types, enums and directives of a .graphqls file, which the GqlSchema may reference.
+type ExternalDefinitions struct {
+	// Types are the kind keywords of the types defined, e.g. type, input, enum, union, interface or scalar, by name
+	Types map[string]string
+	// Directives are the directive definitions, e.g. directive @auth(requires: Role!) on FIELD_DEFINITION
+	Directives []string
+}
+
+// ValidateGqlSchemaWithDefinitions validates the GqlSchema like ValidateGqlSchema, the external definitions, if any,
+// being defined along with the GqlSchema. A type, scalar or directive defined by both is the external one, as when
+// merging, and is not validated.
+func ValidateGqlSchemaWithDefinitions(schema *GqlSchema, strict bool, external *ExternalDefinitions) Diagnostics {
+	v := schemaValidator{
+		severity:   SeverityWarning,
+		kinds:      make(map[string]string),
+		external:   make(map[string]bool),
+		directives: make(map[string]directiveDefinition),
+	}
+	if strict {
+		v.severity = SeverityError
+	}
+	for _, definition := range schema.Directives {
+		if parsed, err := parseDirectiveDefinition(definition.Definition); err == nil {
+			v.directives[parsed.name] = parsed
+		}
+	}
+	v.linked = linkedDirectives(schema.SchemaDirectives)
+	// The directive definitions of the GqlSchema, unless replaced by external ones
+	generated := make(map[string]directiveDefinition, len(v.directives))
+	for name, definition := range v.directives {
+		generated[name] = definition
+	}
+	if external != nil {
+		for _, definition := range external.Directives {
+			if parsed, err := parseDirectiveDefinition(definition); err == nil {
+				v.directives[parsed.name] = parsed
+				delete(generated, parsed.name)
+			}
+		}
+		for name, kind := range external.Types {
+			v.kinds[name] = kind
+			v.external[name] = true
+		}
+	}
+
+	for _, scalar := range schema.Scalars {
+		scalarType := GqlSchemaType{Name: scalar.Name}
+		if v.external[scalar.Name] {
+			continue
+		}
+		v.validateTypeName(scalarType, "scalar")
+		v.define(scalarType, "scalar")
+	}
+	for _, schemaType := range schema.Types {
+		if v.external[schemaType.Name] || schemaType.Extend {
+			continue
+		}
+		v.validateTypeName(schemaType, kindKeyword(schemaType))
+		v.define(schemaType, kindKeyword(schemaType))
+	}
+	for _, schemaType := range schema.Types {
+		// A type defined by the ExternalDefinitions is not generated, unlike the extensions of the types they define
+		if v.external[schemaType.Name] && !schemaType.Extend {
+			continue
+		}
+		if schemaType.Extend {
+			v.validateExtension(schemaType)
+		}
+		v.validateFields(schemaType)
+		v.validateDirectives(schemaType)
+	}
+	for _, definition := range schema.Directives {
+		if parsed, ok := generated[definition.Name]; ok {
+			v.validateDirectiveDefinition(parsed)
+		}
+	}
+	return v.diagnostics
+}
+
+// outputKinds are the kind keywords of the types that can only be used as output types.
+var outputKinds = map[string]bool{KindObject: true, "interface": true, "union": true}
+
+// schemaValidator validates a GqlSchema, collecting the Diagnostics of the violations.
+type schemaValidator struct {
+	severity    Severity
+	kinds       map[string]string              // kinds are the kind keywords of the scalars and types defined, by name
+	external    map[string]bool                // external are the types defined by the ExternalDefinitions, by name
+	directives  map[string]directiveDefinition // directives are the directives defined, by name
+	linked      map[string]bool                // linked are the directives imported by the @link of the schema
+	diagnostics Diagnostics
+}
+
+// report records a violation about the field of the type, or about the type if the field is empty, positioned at the
+// field if its position is known.
+func (v *schemaValidator) report(schemaType GqlSchemaType, field string, code string, message string) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity:   v.severity,
+		Code:       code,
+		Position:   schemaType.fieldPosition(field),
+		StructName: schemaType.Name,
+		FieldName:  field,
+		Message:    message,
+	})
+}
+
+// validateTypeName validates the name of a type or scalar of the kind keyword.
+func (v *schemaValidator) validateTypeName(schemaType GqlSchemaType, kind string) {
+	if !gqlNameRegexp.MatchString(schemaType.Name) || strings.HasPrefix(schemaType.Name, "__") {
+		v.report(schemaType, "", CodeInvalidName, fmt.Sprintf("%s name %q is not a valid GraphQL name", kind, schemaType.Name))
+		return
+	}
+	if first := rune(schemaType.Name[0]); unicode.IsLower(first) {
+		v.report(schemaType, "", CodeInvalidName, fmt.Sprintf("%s name %s is not capitalized, as GraphQL type names are", kind, schemaType.Name))
+	}
+}
+
+// define records the type or scalar of the kind keyword, reporting it if its name is already defined.
+func (v *schemaValidator) define(schemaType GqlSchemaType, kind string) {
+	if gqlBuiltinScalars[schemaType.Name] {
+		v.report(schemaType, "", CodeDuplicateName, fmt.Sprintf("%s %s redefines a built-in scalar", kind, schemaType.Name))
+		return
+	}
+	if previous, ok := v.kinds[schemaType.Name]; ok {
+		v.report(schemaType, "", CodeDuplicateName, fmt.Sprintf("%s %s is already defined as a %s", kind, schemaType.Name, previous))
+		return
+	}
+	v.kinds[schemaType.Name] = kind
+}
+
+// validateFields validates the fields of a type.
+func (v *schemaValidator) validateFields(schemaType GqlSchemaType) {
+	if len(schemaType.Fields) == 0 {
+		v.report(schemaType, "", CodeEmptyType, fmt.Sprintf("%s %s has no field, a GraphQL type must define at least one", kindKeyword(schemaType), schemaType.Name))
+	}
+
+	fieldNames := make(map[string]bool, len(schemaType.Fields))
+	for _, field := range schemaType.Fields {
+		if !gqlNameRegexp.MatchString(field.Name) || strings.HasPrefix(field.Name, "__") {
+			v.report(schemaType, field.Name, CodeInvalidName, fmt.Sprintf("field name %q is not a valid GraphQL name", field.Name))
+		}
+		if fieldNames[field.Name] {
+			v.report(schemaType, field.Name, CodeDuplicateName, fmt.Sprintf("field %s is defined more than once", field.Name))
+		}
+		fieldNames[field.Name] = true
+
+		namedType := namedGqlType(field.Type)
+		kind, defined := v.kinds[namedType]
+		switch {
+		case gqlBuiltinScalars[namedType]:
+		case !defined:
+			v.report(schemaType, field.Name, CodeUndefinedType, fmt.Sprintf("type %s is not defined in the schema", namedType))
+		case schemaType.IsInput() && outputKinds[kind]:
+			v.report(schemaType, field.Name, CodeTypePosition, fmt.Sprintf("input field cannot use the output type %s", namedType))
+		case !schemaType.IsInput() && kind == KindInput:
+			v.report(schemaType, field.Name, CodeTypePosition, fmt.Sprintf("output field cannot use the input type %s", namedType))
+		}
+
+		argumentNames := make(map[string]bool, len(field.Arguments))
+		for _, argument := range field.Arguments {
+			if argumentNames[argument.Name] {
+				v.report(schemaType, field.Name, CodeDuplicateName, fmt.Sprintf("argument %s is defined more than once", argument.Name))
+			}
+			argumentNames[argument.Name] = true
+			v.validateArgumentType(schemaType, field.Name, argument.Name, namedGqlType(argument.Type))
+		}
+	}
+}
+
+// validateArgumentType validates the named type of the argument of the field of the type, or of the directive
+// definition named like the type if the field is empty: a built-in scalar, or an input type, scalar or enum of the
+// schema.
+func (v *schemaValidator) validateArgumentType(schemaType GqlSchemaType, field string, argument string, namedType string) {
+	kind, defined := v.kinds[namedType]
+	switch {
+	case gqlBuiltinScalars[namedType]:
+	case !defined:
+		v.report(schemaType, field, CodeUndefinedType, fmt.Sprintf("argument %s: type %s is not defined in the schema", argument, namedType))
+	case outputKinds[kind]:
+		v.report(schemaType, field, CodeTypePosition, fmt.Sprintf("argument %s cannot use the output type %s", argument, namedType))
+	}
+}
+
+// validateExtension validates that the type extension extends a type of the schema, or of the ExternalDefinitions,
+// of the same kind.
+func (v *schemaValidator) validateExtension(schemaType GqlSchemaType) {
+	kind, defined := v.kinds[schemaType.Name]
+	switch {
+	case !defined:
+		v.report(schemaType, "", CodeUndefinedType, fmt.Sprintf("extend %s %s has no %s %s to extend", kindKeyword(schemaType), schemaType.Name, kindKeyword(schemaType), schemaType.Name))
+	case kind != kindKeyword(schemaType):
+		v.report(schemaType, "", CodeUndefinedType, fmt.Sprintf("extend %s %s cannot extend the %s %s", kindKeyword(schemaType), schemaType.Name, kind, schemaType.Name))
+	}
+}
+
+// validateDirectiveDefinition validates the types of the arguments of the directive definition, reported for a type
+// named like the directive, e.g. @auth.
+func (v *schemaValidator) validateDirectiveDefinition(definition directiveDefinition) {
+	directiveType := GqlSchemaType{Name: "@" + definition.name}
+	for _, name := range definition.argumentNames {
+		v.validateArgumentType(directiveType, "", name, definition.argumentTypes[name])
+	}
+}
@@ -0,0 +1,197 @@
+package conversion
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// TestValidateGqlSchema is a unit test for the ValidateGqlSchema function.
+func TestValidateGqlSchema(t *testing.T) {
+	position := token.Position{Filename: "models.go", Line: 3, Column: 6}
+	tests := []struct {
+		name   string
+		schema *GqlSchema
+		want   []string
+	}{
+		{
+			name: "Valid",
+			schema: &GqlSchema{
+				Scalars: []GqlSchemaScalar{{Name: "BigInt"}},
+				Types: []GqlSchemaType{
+					{Name: "Article", Fields: []GqlSchemaField{{Name: "views", Type: "BigInt"}, {Name: "author", Type: "User"}}},
+					{Name: "User", Fields: []GqlSchemaField{{Name: "tags", Type: "[String!]", NonNull: true}}},
+					{Name: "UserInput", Kind: KindInput, Fields: []GqlSchemaField{{Name: "id", Type: "ID"}}},
+				},
+			},
+		},
+		{
+			name: "EmptyType",
+			schema: &GqlSchema{
+				Types: []GqlSchemaType{{Name: "Another", Position: position}},
+			},
+			want: []string{"models.go:3:6: warning: Another: type Another has no field, a GraphQL type must define at least one [empty-type]"},
+		},
+		{
+			name: "FieldPosition",
+			schema: &GqlSchema{
+				Types: []GqlSchemaType{{Name: "Article", Position: position, Fields: []GqlSchemaField{
+					{Name: "published_at", Type: "Time", Position: token.Position{Filename: "models.go", Line: 5, Column: 2}},
+				}}},
+			},
+			want: []string{"models.go:5:2: warning: Article.published_at: type Time is not defined in the schema [undefined-type]"},
+		},
+		{
+			name: "UndefinedAndLowerCase",
+			schema: &GqlSchema{
+				Scalars: []GqlSchemaScalar{{Name: "error"}},
+				Types: []GqlSchemaType{
+					{Name: "Article", Fields: []GqlSchemaField{{Name: "published_at", Type: "Time"}, {Name: "err", Type: "error"}}},
+				},
+			},
+			want: []string{
+				"warning: error: scalar name error is not capitalized, as GraphQL type names are [invalid-name]",
+				"warning: Article.published_at: type Time is not defined in the schema [undefined-type]",
+			},
+		},
+		{
+			name: "Duplicates",
+			schema: &GqlSchema{
+				Scalars: []GqlSchemaScalar{{Name: "User"}, {Name: "String"}},
+				Types: []GqlSchemaType{
+					{Name: "User", Fields: []GqlSchemaField{{Name: "name", Type: "String"}, {Name: "name", Type: "String"}}},
+				},
+			},
+			want: []string{
+				"warning: String: scalar String redefines a built-in scalar [duplicate-name]",
+				"warning: User: type User is already defined as a scalar [duplicate-name]",
+				"warning: User.name: field name is defined more than once [duplicate-name]",
+			},
+		},
+		{
+			name: "InvalidNames",
+			schema: &GqlSchema{
+				Types: []GqlSchemaType{
+					{Name: "__Meta", Fields: []GqlSchemaField{{Name: "first-name", Type: "String"}}},
+				},
+			},
+			want: []string{
+				"warning: __Meta: type name \"__Meta\" is not a valid GraphQL name [invalid-name]",
+				"warning: __Meta.first-name: field name \"first-name\" is not a valid GraphQL name [invalid-name]",
+			},
+		},
+		{
+			name: "TypePositions",
+			schema: &GqlSchema{
+				Types: []GqlSchemaType{
+					{Name: "Article", Fields: []GqlSchemaField{{Name: "input", Type: "ArticleInput"}}},
+					{Name: "ArticleInput", Kind: KindInput, Fields: []GqlSchemaField{{Name: "author", Type: "[Article]"}}},
+				},
+			},
+			want: []string{
+				"warning: Article.input: output field cannot use the input type ArticleInput [type-position]",
+				"warning: ArticleInput.author: input field cannot use the output type Article [type-position]",
+			},
+		},
+		{
+			name: "Arguments",
+			schema: &GqlSchema{
+				Types: []GqlSchemaType{
+					{Name: "Article", Fields: []GqlSchemaField{{Name: "comments", Type: "[Article]", Arguments: []GqlSchemaArgument{
+						{Name: "first", Type: "Int"},
+						{Name: "after", Type: "Cursor"},
+						{Name: "where", Type: "Article!"},
+						{Name: "first", Type: "Int"},
+					}}}},
+				},
+			},
+			want: []string{
+				"warning: Article.comments: argument after: type Cursor is not defined in the schema [undefined-type]",
+				"warning: Article.comments: argument where cannot use the output type Article [type-position]",
+				"warning: Article.comments: argument first is defined more than once [duplicate-name]",
+			},
+		},
+		{
+			name: "DirectiveArguments",
+			schema: &GqlSchema{
+				Directives: []GqlSchemaDirectiveDefinition{
+					{Name: "auth", Definition: "directive @auth(requires: [Role!]!, user: User, reason: String) on OBJECT"},
+				},
+				Types: []GqlSchemaType{{Name: "User", Fields: []GqlSchemaField{{Name: "name", Type: "String"}}}},
+			},
+			want: []string{
+				"warning: @auth: argument requires: type Role is not defined in the schema [undefined-type]",
+				"warning: @auth: argument user cannot use the output type User [type-position]",
+			},
+		},
+		{
+			name: "Extensions",
+			schema: &GqlSchema{
+				Types: []GqlSchemaType{
+					{Name: "Article", Fields: []GqlSchemaField{{Name: "title", Type: "String"}}},
+					{Name: "Query", Extend: true, Fields: []GqlSchemaField{{Name: "articles", Type: "[Article]"}}},
+					{Name: "Article", Kind: KindInput, Extend: true, Fields: []GqlSchemaField{{Name: "body", Type: "String"}}},
+					{Name: "Article", Extend: true, Fields: []GqlSchemaField{{Name: "views", Type: "Int"}}},
+				},
+			},
+			want: []string{
+				"warning: Query: extend type Query has no type Query to extend [undefined-type]",
+				"warning: Article: extend input Article cannot extend the type Article [undefined-type]",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, diagnostic := range ValidateGqlSchema(tt.schema, false) {
+				got = append(got, diagnostic.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("ValidateGqlSchema() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	strict := ValidateGqlSchema(&GqlSchema{Types: []GqlSchemaType{{Name: "Another"}}}, true)
+	if !strict.HasErrors() {
+		t.Errorf("ValidateGqlSchema() in strict mode = %v, want errors", strict)
+	}
+}
+
+// TestValidateGqlSchemaWithDefinitions is a unit test for the ValidateGqlSchemaWithDefinitions function, validating
+// a schema referencing hand-written definitions.
+func TestValidateGqlSchemaWithDefinitions(t *testing.T) {
+	schema := &GqlSchema{
+		Types: []GqlSchemaType{
+			{Name: "Article", Directives: []GqlSchemaDirective{{Name: "auth", Arguments: []GqlSchemaDirectiveArg{{Name: "requires", Value: "ADMIN"}}}}, Fields: []GqlSchemaField{
+				{Name: "status", Type: "Status"},
+				{Name: "result", Type: "SearchResult"},
+			}},
+			{Name: "ArticleInput", Kind: KindInput, Fields: []GqlSchemaField{{Name: "result", Type: "SearchResult"}}},
+			// Replaced by the hand-written type of the same name
+			{Name: "User"},
+			{Name: "Query", Extend: true, Fields: []GqlSchemaField{
+				{Name: "articles", Type: "[Article]", Arguments: []GqlSchemaArgument{{Name: "status", Type: "Status"}}},
+			}},
+		},
+	}
+	external := &ExternalDefinitions{
+		Types:      map[string]string{"Status": "enum", "SearchResult": "union", "User": "type", "Query": "type"},
+		Directives: []string{"directive @auth(requires: Role!) on OBJECT"},
+	}
+	want := []string{
+		"warning: ArticleInput.result: input field cannot use the output type SearchResult [type-position]",
+	}
+
+	var got []string
+	for _, diagnostic := range ValidateGqlSchemaWithDefinitions(schema, false, external) {
+		got = append(got, diagnostic.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ValidateGqlSchemaWithDefinitions() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if len(ValidateGqlSchema(schema, false)) <= len(want) {
+		t.Errorf("ValidateGqlSchema() without the definitions = %v, want more diagnostics", ValidateGqlSchema(schema, false))
+	}
+}
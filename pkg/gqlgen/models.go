@@ -0,0 +1,302 @@
+// Package gqlgen provides functionalities to bind the GraphQL schema built with package
+// github.com/VintageOps/structogqlgen/pkg/conversion to the Go types through the models section of the gqlgen
+// configuration file, see https://gqlgen.com/config/
+package gqlgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+	"github.com/VintageOps/structogqlgen/pkg/output"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the default name of the gqlgen configuration file.
+const ConfigFileName = "gqlgen.yml"
+
+// DefaultScalarModels are the Go types implementing the custom scalars generated by the conversion, by scalar name,
+// used when no other implementation is chosen.
+var DefaultScalarModels = map[string]string{
+	"BigInt": "github.com/99designs/gqlgen/graphql.Int64",
+}
+
+// Model represents an entry of the models section, binding a GraphQL type or scalar to a Go type.
+type Model struct {
+	Name   string       // Name is the name of the GraphQL type or scalar
+	Model  string       // Model is the Go type qualified by its import path
+	Fields []FieldModel // Fields are the fields of the type needing a configuration, if any
+}
+
+// FieldModel represents the configuration of a field of a Model.
+type FieldModel struct {
+	Name      string // Name is the name of the GraphQL field
+	FieldName string // FieldName is the name of the Go struct field, set when gqlgen would not match it by name
+	Resolver  bool   // Resolver is true if the field cannot be bound to a Go struct field and needs a resolver
+}
+
+// Models returns the Models of the GqlSchema: the object and input object types bound to their Go struct, and the
+// custom scalars bound to the Go type of scalarModels, or else the Go named type converted into the scalar, or else
+// the one of DefaultScalarModels. It also returns the names of the scalars without any Go type, which gqlgen cannot bind.
+//
+// A field without Go struct field, e.g. the placeholder of an empty type, or whose type is a type without Go struct,
+// e.g. one built from a map, needs a resolver.
+func Models(schema *conversion.GqlSchema, scalarModels map[string]string) ([]Model, []string) {
+	generated := make(map[string]bool)
+	for _, schemaType := range schema.Types {
+		if schemaType.GoModel == "" {
+			generated[schemaType.Name] = true
+		}
+	}
+
+	var models []Model
+	var unbound []string
+	for _, scalar := range schema.Scalars {
+		goType := scalarModels[scalar.Name]
+		if goType == "" {
+			goType = scalar.GoModel
+		}
+		if goType == "" {
+			goType = DefaultScalarModels[scalar.Name]
+		}
+		if goType == "" {
+			unbound = append(unbound, scalar.Name)
+			continue
+		}
+		models = append(models, Model{Name: scalar.Name, Model: goType})
+	}
+	for _, schemaType := range schema.Types {
+		// gqlgen generates the models of the types without Go struct
+		if schemaType.GoModel == "" {
+			continue
+		}
+		model := Model{Name: schemaType.Name, Model: schemaType.GoModel}
+		for _, field := range schemaType.Fields {
+			switch {
+			case field.GoName == "" || generated[strings.Trim(field.Type, "[]!")]:
+				model.Fields = append(model.Fields, FieldModel{Name: field.Name, Resolver: true})
+			case !conversion.EqualGqlgenFieldName(field.Name, field.GoName):
+				model.Fields = append(model.Fields, FieldModel{Name: field.Name, FieldName: field.GoName})
+			}
+		}
+		models = append(models, model)
+	}
+	return models, unbound
+}
+
+// UpdateFile updates the models section of the gqlgen configuration file at filePath with the Models, see
+// UpdateContent, creating the file if it does not exist. It returns true if the file was written.
+func UpdateFile(filePath string, models []Model) (bool, error) {
+	existing, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to read the gqlgen configuration file, error was: %v", err)
+	}
+	content, err := UpdateContent(string(existing), models)
+	if err != nil {
+		return false, fmt.Errorf("failed to update the models of %s: %v", filePath, err)
+	}
+	return output.WriteFile(filePath, content)
+}
+
+// UpdateContent returns the content of the gqlgen configuration file existing with the Models added to its models
+// section, or updated when they already have an entry. The model of an entry is replaced unless it already lists the
+// Go type, and the fields of the Models are set, the other keys of the entry left unchanged. The entries of the other
+// types and their comments are preserved. Only the lines of the models section are rewritten, which drops the blank
+// lines between its entries, the rest of the content being left byte-for-byte unchanged, and the section is appended if
+// the content has none. The content is returned unchanged if it already holds the Models.
+func UpdateContent(existing string, models []Model) (string, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(existing), &document); err != nil {
+		return "", err
+	}
+	if document.Kind == 0 {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("the configuration is not a mapping")
+	}
+	// The keys of the section and of the following one, if any, before the section is added
+	modelsKey, nextKey := sectionKeys(root, "models")
+
+	updater := &updater{}
+	modelsNode, err := updater.mapping(root, "models")
+	if err != nil {
+		return "", err
+	}
+	for _, model := range models {
+		entry, err := updater.mapping(modelsNode, model.Name)
+		if err != nil {
+			return "", err
+		}
+		updater.setModel(entry, model.Model)
+		if len(model.Fields) == 0 {
+			continue
+		}
+		fields, err := updater.mapping(entry, "fields")
+		if err != nil {
+			return "", fmt.Errorf("models.%s: %v", model.Name, err)
+		}
+		for _, field := range model.Fields {
+			fieldEntry, err := updater.mapping(fields, field.Name)
+			if err != nil {
+				return "", fmt.Errorf("models.%s.fields: %v", model.Name, err)
+			}
+			if field.Resolver {
+				updater.setScalar(fieldEntry, "resolver", "!!bool", "true")
+			}
+			if field.FieldName != "" {
+				updater.setScalar(fieldEntry, "fieldName", "!!str", field.FieldName)
+			}
+		}
+	}
+	if !updater.changed {
+		return existing, nil
+	}
+
+	// A configuration written as a flow mapping has no lines of its own for the section
+	if root.Style&yaml.FlowStyle != 0 {
+		return encode(&document)
+	}
+	section := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: "models"}, modelsNode}}
+	if modelsKey != nil {
+		// The comments above the key are left in place
+		key := *modelsKey
+		key.HeadComment = ""
+		section.Content[0] = &key
+	}
+	content, err := encode(section)
+	if err != nil {
+		return "", err
+	}
+	if modelsKey == nil {
+		if existing != "" && !strings.HasSuffix(existing, "\n") {
+			existing += "\n"
+		}
+		return existing + content, nil
+	}
+	start, end := sectionRange(existing, modelsKey, nextKey)
+	return existing[:start] + content + existing[end:], nil
+}
+
+// sectionKeys returns the key of the section of the root mapping node and the key following it, nil if there is none.
+func sectionKeys(root *yaml.Node, section string) (*yaml.Node, *yaml.Node) {
+	for idx := 0; idx+1 < len(root.Content); idx += 2 {
+		if root.Content[idx].Value != section {
+			continue
+		}
+		if idx+2 < len(root.Content) {
+			return root.Content[idx], root.Content[idx+2]
+		}
+		return root.Content[idx], nil
+	}
+	return nil, nil
+}
+
+// sectionRange returns the byte offsets of the lines of content holding the section of the key: from the line of the
+// key to the last line before the next key, if any, and its comments, the trailing blank lines excluded.
+func sectionRange(content string, key *yaml.Node, nextKey *yaml.Node) (int, int) {
+	lines := strings.SplitAfter(content, "\n")
+	lastLine := len(lines)
+	if nextKey != nil {
+		lastLine = nextKey.Line - 1
+		if nextKey.HeadComment != "" {
+			lastLine -= strings.Count(nextKey.HeadComment, "\n") + 1
+		}
+	}
+	for lastLine > key.Line && strings.TrimSpace(lines[lastLine-1]) == "" {
+		lastLine--
+	}
+	start, end := 0, 0
+	for idx := 0; idx < lastLine; idx++ {
+		if idx < key.Line-1 {
+			start += len(lines[idx])
+		}
+		end += len(lines[idx])
+	}
+	return start, end
+}
+
+// encode returns the YAML encoding of the node, indented with 2 spaces.
+func encode(node *yaml.Node) (string, error) {
+	var content bytes.Buffer
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+	return content.String(), nil
+}
+
+// updater updates the nodes of a YAML document, keeping track of whether any was changed.
+type updater struct {
+	changed bool
+}
+
+// value returns the value of the key of the mapping node, or nil if the mapping has no such key.
+func value(mapping *yaml.Node, key string) *yaml.Node {
+	for idx := 0; idx+1 < len(mapping.Content); idx += 2 {
+		if mapping.Content[idx].Value == key {
+			return mapping.Content[idx+1]
+		}
+	}
+	return nil
+}
+
+// mapping returns the mapping value of the key of the mapping node, adding it if the mapping has no such key or if
+// its value is null. It returns an error if the value is not a mapping.
+func (u *updater) mapping(mapping *yaml.Node, key string) (*yaml.Node, error) {
+	node := value(mapping, key)
+	if node == nil {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, node)
+		u.changed = true
+		return node, nil
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: node.HeadComment, LineComment: node.LineComment}
+		u.changed = true
+		return node, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s is not a mapping", key)
+	}
+	return node, nil
+}
+
+// setScalar sets the value of the key of the mapping node to the scalar value of type tag, keeping the comments of
+// the previous value.
+func (u *updater) setScalar(mapping *yaml.Node, key string, tag string, scalar string) {
+	node := value(mapping, key)
+	if node == nil {
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: scalar})
+		u.changed = true
+		return
+	}
+	if node.Kind == yaml.ScalarNode && node.Value == scalar {
+		return
+	}
+	*node = yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: scalar, LineComment: node.LineComment}
+	u.changed = true
+}
+
+// setModel sets the model of the entry node to the Go type goType, unless it already lists it.
+func (u *updater) setModel(entry *yaml.Node, goType string) {
+	if node := value(entry, "model"); node != nil && node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			if item.Value == goType {
+				return
+			}
+		}
+	}
+	u.setScalar(entry, "model", "!!str", goType)
+}
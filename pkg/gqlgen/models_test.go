@@ -0,0 +1,174 @@
+package gqlgen
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// TestModels is a unit test for the Models function.
+func TestModels(t *testing.T) {
+	schema := &conversion.GqlSchema{
+		Scalars: []conversion.GqlSchemaScalar{{Name: "BigInt"}, {Name: "Status", GoModel: "github.com/acme/models.Status"}, {Name: "UnsafePointer"}},
+		Types: []conversion.GqlSchemaType{
+			{Name: "Article", GoModel: "github.com/acme/models.Article", Fields: []conversion.GqlSchemaField{
+				{Name: "title", Type: "String", GoName: "Title"},
+				{Name: "published", Type: "BigInt", GoName: "PublishedAt"},
+				{Name: "labels", Type: "[LabelsMap]", GoName: "Labels"},
+				{Name: "_empty", Type: "Boolean"},
+			}},
+			{Name: "LabelsMap", Fields: []conversion.GqlSchemaField{{Name: "key", Type: "String", GoName: "key"}}},
+		},
+	}
+	wantModels := []Model{
+		{Name: "BigInt", Model: "github.com/acme/scalars.Int64"},
+		{Name: "Status", Model: "github.com/acme/models.Status"},
+		{Name: "Article", Model: "github.com/acme/models.Article", Fields: []FieldModel{
+			{Name: "published", FieldName: "PublishedAt"},
+			{Name: "labels", Resolver: true},
+			{Name: "_empty", Resolver: true},
+		}},
+	}
+
+	models, unbound := Models(schema, map[string]string{"BigInt": "github.com/acme/scalars.Int64"})
+	if !reflect.DeepEqual(models, wantModels) {
+		t.Errorf("Models() = %+v, want %+v", models, wantModels)
+	}
+	if !reflect.DeepEqual(unbound, []string{"UnsafePointer"}) {
+		t.Errorf("Models() unbound = %v, want [UnsafePointer]", unbound)
+	}
+
+	// Without any choice, BigInt is bound to its default implementation
+	models, _ = Models(schema, nil)
+	if models[0].Model != DefaultScalarModels["BigInt"] {
+		t.Errorf("Models() BigInt model = %s, want %s", models[0].Model, DefaultScalarModels["BigInt"])
+	}
+}
+
+// TestUpdateContent is a unit test for the UpdateContent function.
+func TestUpdateContent(t *testing.T) {
+	models := []Model{
+		{Name: "Article", Model: "github.com/acme/models.Article", Fields: []FieldModel{{Name: "labels", Resolver: true}, {Name: "published", FieldName: "PublishedAt"}}},
+		{Name: "ID", Model: "github.com/99designs/gqlgen/graphql.ID"},
+	}
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Empty",
+			existing: "",
+			want: "models:\n  Article:\n    model: github.com/acme/models.Article\n    fields:\n      labels:\n        resolver: true\n" +
+				"      published:\n        fieldName: PublishedAt\n  ID:\n    model: github.com/99designs/gqlgen/graphql.ID\n",
+		},
+		{
+			name: "Existing",
+			existing: "# gqlgen configuration\nschema:\n  - graph/*.graphqls # the schema files\nmodels:\n  # Identifiers\n  ID:\n    model:\n" +
+				"      - github.com/99designs/gqlgen/graphql.ID\n      - github.com/99designs/gqlgen/graphql.Int\n  Article:\n    model: models.Old\n" +
+				"    fields:\n      labels:\n        resolver: false\n      id:\n        resolver: true\n",
+			want: "# gqlgen configuration\nschema:\n  - graph/*.graphqls # the schema files\nmodels:\n  # Identifiers\n  ID:\n    model:\n" +
+				"      - github.com/99designs/gqlgen/graphql.ID\n      - github.com/99designs/gqlgen/graphql.Int\n  Article:\n" +
+				"    model: github.com/acme/models.Article\n    fields:\n      labels:\n        resolver: true\n      id:\n        resolver: true\n" +
+				"      published:\n        fieldName: PublishedAt\n",
+		},
+		{
+			// The configuration written by gqlgen init, with its blank lines and comments around the models section
+			name: "GqlgenInit",
+			existing: gqlgenInitConfig("models:\n  ID:\n    model:\n      - github.com/99designs/gqlgen/graphql.ID\n\n" +
+				"  # The articles\n  Article:\n    model: models.Old\n"),
+			want: gqlgenInitConfig("models:\n  ID:\n    model:\n      - github.com/99designs/gqlgen/graphql.ID\n" +
+				"  # The articles\n  Article:\n    model: github.com/acme/models.Article\n    fields:\n      labels:\n" +
+				"        resolver: true\n      published:\n        fieldName: PublishedAt\n"),
+		},
+		{
+			name:     "NoModels",
+			existing: "schema:\n  - graph/*.graphqls\n\n# The models are bound by structogqlgen\nresolver:\n  dir: graph",
+			want: "schema:\n  - graph/*.graphqls\n\n# The models are bound by structogqlgen\nresolver:\n  dir: graph\nmodels:\n" +
+				"  ID:\n    model: github.com/99designs/gqlgen/graphql.ID\n",
+		},
+		{
+			// The content is not reformatted when it already holds the models
+			name: "UpToDate",
+			existing: "models:\n    Article:\n        model: github.com/acme/models.Article\n        fields:\n            labels: {resolver: true}\n" +
+				"            published: {fieldName: PublishedAt}\n    ID: {model: [github.com/99designs/gqlgen/graphql.ID]}\n",
+			want: "models:\n    Article:\n        model: github.com/acme/models.Article\n        fields:\n            labels: {resolver: true}\n" +
+				"            published: {fieldName: PublishedAt}\n    ID: {model: [github.com/99designs/gqlgen/graphql.ID]}\n",
+		},
+		{
+			name:     "NullModels",
+			existing: "models:\n",
+			want:     "models:\n  ID:\n    model: github.com/99designs/gqlgen/graphql.ID\n",
+		},
+		{name: "NotMapping", existing: "- a\n", wantErr: true},
+		{name: "ModelsNotMapping", existing: "models: []\n", wantErr: true},
+		{name: "Invalid", existing: "models: [\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modelsToSet := models
+			if tt.name == "NullModels" || tt.name == "NoModels" {
+				modelsToSet = models[1:]
+			}
+			got, err := UpdateContent(tt.existing, modelsToSet)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UpdateContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// gqlgenInitConfig returns the configuration file written by gqlgen init, its models section replaced with models.
+func gqlgenInitConfig(models string) string {
+	return `# Where are all the schema files located? globs are supported eg  src/**/*.graphqls
+schema:
+  - graph/*.graphqls
+
+# Where should the generated server code go?
+exec:
+  filename: graph/generated.go
+  package: graph
+
+# This section declares type mapping between the GraphQL and go type systems
+#
+# The first line in each type will be used as defaults for resolver arguments and
+# modelgen, the others will be allowed when binding to fields. Configure them to
+# your liking
+` + models + `
+# Where should the resolver implementations go?
+resolver:
+  layout: follow-schema
+  dir: graph
+  package: graph
+
+# Optional: turn on to skip generation of ComplexityRoot struct content
+# omit_complexity: false
+`
+}
+
+// TestUpdateFile is a unit test for the UpdateFile function.
+func TestUpdateFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), ConfigFileName)
+	models := []Model{{Name: "BigInt", Model: DefaultScalarModels["BigInt"]}}
+	if written, err := UpdateFile(filePath, models); err != nil || !written {
+		t.Fatalf("UpdateFile() = %v, %v, want the file written", written, err)
+	}
+	if written, err := UpdateFile(filePath, models); err != nil || written {
+		t.Errorf("UpdateFile() on an up to date file = %v, %v, want the file left unchanged", written, err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "models:\n  BigInt:\n    model: " + DefaultScalarModels["BigInt"] + "\n"; string(content) != want {
+		t.Errorf("UpdateFile() content = %q, want %q", content, want)
+	}
+}
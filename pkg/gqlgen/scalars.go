@@ -0,0 +1,349 @@
+package gqlgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+	"github.com/VintageOps/structogqlgen/pkg/output"
+)
+
+// graphqlPackage is the import path of the gqlgen runtime package the marshalers use.
+const graphqlPackage = "github.com/99designs/gqlgen/graphql"
+
+// ScalarsFileHeader is the comment heading the Go files of marshalers written from scratch.
+const ScalarsFileHeader = "// Marshalers of the custom scalars, generated by structogqlgen. Implement the TODO bodies, the functions\n" +
+	"// already defined are left unchanged when the file is generated again."
+
+// WriteScalarsFile writes the marshalers of the scalars into the Go file at filePath, see ScalarsSource, creating the
+// file if it does not exist. When pkgName is empty, the package of a new file is named after its directory.
+// It returns true if the file was written, and the names of the scalars it implements.
+func WriteScalarsFile(filePath string, pkgName string, scalars []conversion.GqlSchemaScalar) (bool, []string, error) {
+	if pkgName == "" {
+		pkgName = packageName(filePath)
+	}
+	existing, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, nil, fmt.Errorf("failed to read the scalars file, error was: %v", err)
+	}
+	content, implemented, err := ScalarsSource(string(existing), pkgName, scalars)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to generate the scalars of %s: %v", filePath, err)
+	}
+	written, err := output.WriteFile(filePath, content)
+	return written, implemented, err
+}
+
+// ScalarsSource returns the Go source existing completed with a MarshalXxx and UnmarshalXxx function pair for each
+// custom scalar, in the external marshalers style of gqlgen, see https://gqlgen.com/reference/scalars/. The pairs are
+// only added for the scalars whose functions are not defined yet, so that the functions implemented by hand are kept.
+// When existing is empty, a new file of the package pkgName is returned.
+//
+// The functions convert the Go type converted into the scalar. They are implemented for the types whose underlying type
+// is a boolean, number or string, for the empty interface and for the types implementing json.Marshaler, and left with
+// a TODO body otherwise. The scalars whose Go type implements gqlgen's graphql.Marshaler need no functions and are left
+// out. It also returns the names of the scalars implemented by the source, sorted.
+func ScalarsSource(existing string, pkgName string, scalars []conversion.GqlSchemaScalar) (string, []string, error) {
+	defined := make(map[string]bool)
+	imports := newImports()
+	var file *ast.File
+	if strings.TrimSpace(existing) != "" {
+		var err error
+		file, err = parser.ParseFile(token.NewFileSet(), "", existing, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+				defined[funcDecl.Name.Name] = true
+			}
+		}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports.add(importPath, name)
+		}
+	}
+
+	var stubs bytes.Buffer
+	var implemented []string
+	for _, scalar := range scalars {
+		if scalar.Marshaler == conversion.MarshalerGqlgen {
+			continue
+		}
+		implemented = append(implemented, scalar.Name)
+		if defined["Marshal"+scalar.Name] && defined["Unmarshal"+scalar.Name] {
+			continue
+		}
+		stubs.WriteString("\n")
+		stubs.WriteString(scalarStub(scalar, imports))
+	}
+	for name := range defined {
+		if scalarName, ok := strings.CutPrefix(name, "Marshal"); ok && defined["Unmarshal"+scalarName] && !contains(implemented, scalarName) {
+			implemented = append(implemented, scalarName)
+		}
+	}
+	sort.Strings(implemented)
+	if stubs.Len() == 0 && file != nil {
+		return existing, implemented, nil
+	}
+
+	var source string
+	if file == nil {
+		source = ScalarsFileHeader + "\n\npackage " + pkgName + "\n\n" + imports.block(imports.used()) + stubs.String()
+	} else {
+		source = insertImports(existing, file, imports) + stubs.String()
+	}
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return "", nil, err
+	}
+	return string(formatted), implemented, nil
+}
+
+// scalarStub returns the MarshalXxx and UnmarshalXxx function pair of the scalar, recording the imports they need.
+func scalarStub(scalar conversion.GqlSchemaScalar, imports *importSet) string {
+	goType := "interface{}"
+	var underlying types.Type
+	switch {
+	case scalar.GoType != nil:
+		goType = types.TypeString(scalar.GoType, imports.qualifier)
+		underlying = scalar.GoType.Underlying()
+	case scalar.GoModel != "":
+		idx := strings.LastIndex(scalar.GoModel, ".")
+		goType = imports.qualifier(types.NewPackage(scalar.GoModel[:idx], path.Base(scalar.GoModel[:idx]))) + "." + scalar.GoModel[idx+1:]
+	}
+	graphql := imports.use(graphqlPackage)
+
+	var marshal, unmarshal string
+	basic, isBasic := underlying.(*types.Basic)
+	iface, isInterface := underlying.(*types.Interface)
+	switch {
+	case scalar.Marshaler == conversion.MarshalerJson:
+		marshal = fmt.Sprintf("return %s.MarshalAny(v)", graphql)
+		unmarshal = fmt.Sprintf("var value %s\n\tdata, err := %s.Marshal(v)\n\tif err != nil {\n\t\treturn value, err\n\t}\n"+
+			"\terr = %s.Unmarshal(data, &value)\n\treturn value, err", goType, imports.use("encoding/json"), imports.use("encoding/json"))
+	case isInterface && iface.Empty():
+		marshal = fmt.Sprintf("return %s.MarshalAny(v)", graphql)
+		unmarshal = fmt.Sprintf("return %s.UnmarshalAny(v)", graphql)
+	case isBasic && basic.Info()&types.IsComplex != 0:
+		marshal = fmt.Sprintf("return %s.MarshalString(%s.FormatComplex(%s, 'g', -1, 128))", graphql, imports.use("strconv"), convert(goType, "complex128", "v"))
+		unmarshal = fmt.Sprintf("str, err := %s.UnmarshalString(v)\n\tif err != nil {\n\t\treturn 0, err\n\t}\n\t%s",
+			graphql, convertResult("complex128", goType, imports.use("strconv")+".ParseComplex(str, 128)"))
+	case isBasic && basic.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0:
+		gqlgenType, baseType := basicMarshalers(basic)
+		marshal = fmt.Sprintf("return %s.Marshal%s(%s)", graphql, gqlgenType, convert(goType, baseType, "v"))
+		unmarshal = convertResult(baseType, goType, fmt.Sprintf("%s.Unmarshal%s(v)", graphql, gqlgenType))
+	default:
+		marshal, unmarshal = todoBodies(scalar.Name, goType, imports)
+	}
+	return fmt.Sprintf("// Marshal%[1]s writes v as a %[1]s scalar.\nfunc Marshal%[1]s(v %[2]s) %[3]s.Marshaler {\n\t%[4]s\n}\n\n"+
+		"// Unmarshal%[1]s reads the %[1]s scalar v.\nfunc Unmarshal%[1]s(v interface{}) (%[2]s, error) {\n\t%[5]s\n}\n",
+		scalar.Name, goType, graphql, marshal, unmarshal)
+}
+
+// todoBodies returns the bodies of the marshalers left to implement.
+func todoBodies(scalarName string, goType string, imports *importSet) (string, string) {
+	marshal := fmt.Sprintf("// TODO: write the %s scalar of v\n\treturn %s.Null", scalarName, imports.use(graphqlPackage))
+	unmarshal := fmt.Sprintf("var value %s\n\t// TODO: read the %s scalar v into value\n\treturn value, %s.Errorf(\"unmarshaling %%T into %s is not implemented\", v)",
+		goType, scalarName, imports.use("fmt"), scalarName)
+	return marshal, unmarshal
+}
+
+// basicMarshalers returns the suffix of the gqlgen marshalers of the basic type, e.g. Int64 for MarshalInt64, and the
+// Go type they convert.
+func basicMarshalers(basic *types.Basic) (string, string) {
+	info := basic.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return "Boolean", "bool"
+	case info&types.IsUnsigned != 0:
+		return "Uint64", "uint64"
+	case info&types.IsInteger != 0:
+		return "Int64", "int64"
+	case info&types.IsFloat != 0:
+		return "Float", "float64"
+	}
+	return "String", "string"
+}
+
+// convert returns the Go expression converting the expression expr of type from into the type to.
+func convert(from string, to string, expr string) string {
+	if from == to {
+		return expr
+	}
+	return to + "(" + expr + ")"
+}
+
+// convertResult returns the Go statement returning the result of the call, of type from and an error, converted into the type to.
+func convertResult(from string, to string, call string) string {
+	if from == to {
+		return "return " + call
+	}
+	return fmt.Sprintf("value, err := %s\n\treturn %s, err", call, convert(from, to, "value"))
+}
+
+// contains returns true if the names contain name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// importSet names the packages imported by a Go file, by import path.
+type importSet struct {
+	names   map[string]string // names are the names of the packages by import path
+	taken   map[string]bool   // taken are the names in use
+	present map[string]bool   // present are the import paths already imported by the file
+	needed  map[string]bool   // needed are the import paths used by the generated code
+}
+
+// newImports returns an empty importSet.
+func newImports() *importSet {
+	return &importSet{names: make(map[string]string), taken: make(map[string]bool), present: make(map[string]bool), needed: make(map[string]bool)}
+}
+
+// add records the import path already imported by the file with the name.
+func (imports *importSet) add(importPath string, name string) {
+	imports.names[importPath] = name
+	imports.taken[name] = true
+	imports.present[importPath] = true
+}
+
+// use records the import path as used and returns the name of its package, e.g. graphql.
+func (imports *importSet) use(importPath string) string {
+	return imports.qualifier(types.NewPackage(importPath, path.Base(importPath)))
+}
+
+// qualifier is a types.Qualifier recording the package as used and returning its name, aliased when the name of
+// another package.
+func (imports *importSet) qualifier(pkg *types.Package) string {
+	imports.needed[pkg.Path()] = true
+	if name, ok := imports.names[pkg.Path()]; ok {
+		return name
+	}
+	name := pkg.Name()
+	for idx := 2; imports.taken[name]; idx++ {
+		name = fmt.Sprintf("%s%d", pkg.Name(), idx)
+	}
+	imports.names[pkg.Path()] = name
+	imports.taken[name] = true
+	return name
+}
+
+// used returns the import paths used by the generated code and not imported by the file yet, sorted.
+func (imports *importSet) used() []string {
+	var importPaths []string
+	for importPath := range imports.needed {
+		if !imports.present[importPath] {
+			importPaths = append(importPaths, importPath)
+		}
+	}
+	sort.Strings(importPaths)
+	return importPaths
+}
+
+// spec returns the import spec of the import path, aliased when the name of the package is not the last element of its path.
+func (imports *importSet) spec(importPath string) string {
+	if name := imports.names[importPath]; name != path.Base(importPath) {
+		return name + " " + strconv.Quote(importPath)
+	}
+	return strconv.Quote(importPath)
+}
+
+// block returns the import declaration of the import paths, the standard library ones first, or an empty string if
+// there is none.
+func (imports *importSet) block(importPaths []string) string {
+	if len(importPaths) == 0 {
+		return ""
+	}
+	var std, others []string
+	for _, importPath := range importPaths {
+		if isStdPackage(importPath) {
+			std = append(std, importPath)
+		} else {
+			others = append(others, importPath)
+		}
+	}
+	var block strings.Builder
+	block.WriteString("import (\n")
+	for _, importPath := range std {
+		block.WriteString("\t" + imports.spec(importPath) + "\n")
+	}
+	if len(std) != 0 && len(others) != 0 {
+		block.WriteString("\n")
+	}
+	for _, importPath := range others {
+		block.WriteString("\t" + imports.spec(importPath) + "\n")
+	}
+	block.WriteString(")\n")
+	return block.String()
+}
+
+// isStdPackage returns true if the import path is the one of a package of the standard library, i.e. its first
+// element has no dot.
+func isStdPackage(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// insertImports returns the Go source existing, parsed into file, with the import specs of the imports it misses.
+func insertImports(existing string, file *ast.File, imports *importSet) string {
+	missing := imports.used()
+	if len(missing) == 0 {
+		return existing
+	}
+	// Insert the specs into the first import declaration with parentheses, or else a new declaration after the package clause
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT || !genDecl.Lparen.IsValid() {
+			continue
+		}
+		offset := int(genDecl.Lparen - file.FileStart)
+		var specs strings.Builder
+		for _, importPath := range missing {
+			specs.WriteString("\n\t" + imports.spec(importPath))
+		}
+		return existing[:offset+1] + specs.String() + existing[offset+1:]
+	}
+	offset := int(file.Name.End() - file.FileStart)
+	return existing[:offset] + "\n\n" + strings.TrimSuffix(imports.block(missing), "\n") + existing[offset:]
+}
+
+// packageName returns the name of the package of the Go file at filePath: the name of its directory, without the
+// characters not allowed in an identifier, or scalars if there is none left.
+func packageName(filePath string) string {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return "scalars"
+	}
+	var name strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(filepath.Dir(abs))) {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9' && name.Len() > 0) {
+			name.WriteRune(r)
+		}
+	}
+	if name.Len() == 0 {
+		return "scalars"
+	}
+	return name.String()
+}
@@ -0,0 +1,123 @@
+package gqlgen
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// namedType returns the Go named type name of the package importPath whose underlying type is underlying.
+func namedType(importPath string, name string, underlying types.Type) types.Type {
+	pkg := types.NewPackage(importPath, filepath.Base(importPath))
+	return types.NewNamed(types.NewTypeName(0, pkg, name, nil), underlying, nil)
+}
+
+// TestScalarsSource is a unit test for the ScalarsSource function.
+func TestScalarsSource(t *testing.T) {
+	scalars := []conversion.GqlSchemaScalar{
+		{Name: "BigInt", GoType: types.Typ[types.Uint64]},
+		{Name: "Status", GoType: namedType("github.com/acme/models", "Status", types.Typ[types.Int])},
+		{Name: "Point", GoType: namedType("github.com/acme/models", "Point", types.NewStruct(nil, nil))},
+		{Name: "Upload", Marshaler: conversion.MarshalerGqlgen},
+	}
+	tests := []struct {
+		name            string
+		existing        string
+		wantContains    []string
+		wantNotContains []string
+		wantImplemented []string
+		wantErr         bool
+	}{
+		{
+			name: "NewFile",
+			wantContains: []string{
+				ScalarsFileHeader + "\n\npackage scalars\n",
+				"import (\n\t\"fmt\"\n\n\t\"github.com/99designs/gqlgen/graphql\"\n\t\"github.com/acme/models\"\n)\n",
+				"// MarshalBigInt writes v as a BigInt scalar.\nfunc MarshalBigInt(v uint64) graphql.Marshaler {\n\treturn graphql.MarshalUint64(v)\n}\n",
+				"func UnmarshalBigInt(v interface{}) (uint64, error) {\n\treturn graphql.UnmarshalUint64(v)\n}\n",
+				"func MarshalStatus(v models.Status) graphql.Marshaler {\n\treturn graphql.MarshalInt64(int64(v))\n}\n",
+				"value, err := graphql.UnmarshalInt64(v)\n\treturn models.Status(value), err\n",
+				"// TODO: write the Point scalar of v\n\treturn graphql.Null\n",
+				"return value, fmt.Errorf(\"unmarshaling %T into Point is not implemented\", v)\n",
+			},
+			wantNotContains: []string{"MarshalUpload"},
+			wantImplemented: []string{"BigInt", "Point", "Status"},
+		},
+		{
+			// The pairs already defined are kept, and the imports of the file reused
+			name: "ExistingFile",
+			existing: "package api\n\nimport (\n\tm \"github.com/acme/models\"\n\t\"github.com/99designs/gqlgen/graphql\"\n)\n\n" +
+				"func MarshalStatus(v m.Status) graphql.Marshaler { return graphql.MarshalString(\"done\") }\n\n" +
+				"func UnmarshalStatus(v interface{}) (m.Status, error) { return 0, nil }\n\n" +
+				"func MarshalDate(v string) graphql.Marshaler { return graphql.MarshalString(v) }\n\n" +
+				"func UnmarshalDate(v interface{}) (string, error) { return graphql.UnmarshalString(v) }\n",
+			wantContains: []string{
+				"package api\n\nimport (\n\t\"fmt\"\n\t\"github.com/99designs/gqlgen/graphql\"\n\tm \"github.com/acme/models\"\n)\n",
+				"func MarshalStatus(v m.Status) graphql.Marshaler { return graphql.MarshalString(\"done\") }\n",
+				"func MarshalPoint(v m.Point) graphql.Marshaler {\n",
+				"func MarshalBigInt(v uint64) graphql.Marshaler {\n",
+			},
+			wantNotContains: []string{"graphql.MarshalInt64(int64(v))"},
+			wantImplemented: []string{"BigInt", "Date", "Point", "Status"},
+		},
+		{
+			name:     "ExistingFileWithoutImports",
+			existing: "package api\n",
+			wantContains: []string{
+				"package api\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/99designs/gqlgen/graphql\"\n\t\"github.com/acme/models\"\n)\n",
+			},
+			wantImplemented: []string{"BigInt", "Point", "Status"},
+		},
+		{name: "Invalid", existing: "package api\nfunc {", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, implemented, err := ScalarsSource(tt.existing, "scalars", scalars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ScalarsSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got, want) {
+					t.Errorf("ScalarsSource() = %s\nwant it to contain %q", got, want)
+				}
+			}
+			for _, notWant := range tt.wantNotContains {
+				if strings.Contains(got, notWant) {
+					t.Errorf("ScalarsSource() = %s\nwant it not to contain %q", got, notWant)
+				}
+			}
+			if !reflect.DeepEqual(implemented, tt.wantImplemented) {
+				t.Errorf("ScalarsSource() implemented = %v, want %v", implemented, tt.wantImplemented)
+			}
+		})
+	}
+}
+
+// TestWriteScalarsFile is a unit test for the WriteScalarsFile function.
+func TestWriteScalarsFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-scalars")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(dir, "scalars.go")
+	scalars := []conversion.GqlSchemaScalar{{Name: "BigInt", GoType: types.Typ[types.Int64]}}
+	if written, _, err := WriteScalarsFile(filePath, "", scalars); err != nil || !written {
+		t.Fatalf("WriteScalarsFile() = %v, %v, want the file written", written, err)
+	}
+	if written, _, err := WriteScalarsFile(filePath, "", scalars); err != nil || written {
+		t.Errorf("WriteScalarsFile() on an up to date file = %v, %v, want the file left unchanged", written, err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "\npackage myscalars\n") {
+		t.Errorf("WriteScalarsFile() content = %s, want the package named after the directory", content)
+	}
+}
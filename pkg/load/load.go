@@ -4,10 +4,16 @@ package load
 import (
 	"fmt"
 	"go/ast"
+	"go/build"
 	"go/importer"
 	"go/parser"
 	"go/token"
 	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 )
 
 // Doc: https://github.com/golang/example/blob/master/gotypes/go-types.md
@@ -16,30 +22,177 @@ import (
 type StructDiscovered struct {
 	Name *types.TypeName
 	Obj  *types.Struct
+	Fset *token.FileSet    // Fset is the file set the struct was parsed with, it resolves the positions of the struct and its fields
+	Doc  *ast.CommentGroup // Doc is the doc comment of the struct declaration, if any, e.g. holding a //gql:entity directive
 }
 
+// defaultPkgPath is the package path used when the import path of a package cannot be found from a go.mod file.
+const defaultPkgPath = "mypkg"
+
 // GetStructsFromSourceFile finds all structs defined in a Source File.
 func GetStructsFromSourceFile(sourceFilePath string) ([]StructDiscovered, error) {
 
-	var structTypes []StructDiscovered
-
 	// Parse the provided source file
 	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, sourceFilePath, nil, 0)
+	file, err := parser.ParseFile(fset, sourceFilePath, nil, parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parsed the file, error was: %v", err)
 	}
 
+	var fileNames []string
+	if fileName, err := filepath.Abs(sourceFilePath); err == nil {
+		fileNames = append(fileNames, fileName)
+	}
+	dir := filepath.Dir(sourceFilePath)
+	structTypes, err := getStructsFromFiles(fset, dir, []*ast.File{file}, newImporter(fset, dir, fileNames, false))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(structTypes) == 0 {
+		return structTypes, fmt.Errorf("no structs found")
+	}
+
+	return structTypes, nil
+}
+
+// GetStructsFromPath finds all structs defined in a source file or in the Go package of a directory.
+// If the path ends with "/...", the packages of all the sub-directories are also included.
+// The files of a directory excluded by their build constraints for the current platform are left out, and test files
+// are only included in a package that has no other source files.
+func GetStructsFromPath(sourcePath string) ([]StructDiscovered, error) {
+	recursive := filepath.Base(sourcePath) == "..."
+	if recursive {
+		sourcePath = filepath.Dir(sourcePath)
+	}
+
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the source path, error was: %v", err)
+	}
+	if !info.IsDir() {
+		return GetStructsFromSourceFile(sourcePath)
+	}
+
+	dirs := []string{sourcePath}
+	if recursive {
+		dirs = nil
+		err = filepath.WalkDir(sourcePath, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				name := entry.Name()
+				if path != sourcePath && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+					return filepath.SkipDir
+				}
+				dirs = append(dirs, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk the source directory, error was: %v", err)
+		}
+	}
+
+	fset := token.NewFileSet()
+	var pkgDirs []string
+	pkgFiles := make(map[string][]*ast.File)
+	testsOnly := false
+	for _, dir := range dirs {
+		files, err := parseDir(fset, dir)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		pkgDirs = append(pkgDirs, dir)
+		pkgFiles[dir] = files
+		testsOnly = testsOnly || strings.HasSuffix(fset.File(files[0].Pos()).Name(), "_test.go")
+	}
+	if len(pkgDirs) == 0 {
+		return nil, fmt.Errorf("no structs found")
+	}
+
+	// The dependencies of all the packages are listed by a single go command, as it is slow to start
+	var patterns []string
+	for _, dir := range pkgDirs {
+		if absDir, err := filepath.Abs(dir); err == nil {
+			patterns = append(patterns, absDir)
+		}
+	}
+	imp := newImporter(fset, sourcePath, patterns, testsOnly)
+	var structTypes []StructDiscovered
+	for _, dir := range pkgDirs {
+		dirStructTypes, err := getStructsFromFiles(fset, dir, pkgFiles[dir], imp)
+		if err != nil {
+			return nil, err
+		}
+		structTypes = append(structTypes, dirStructTypes...)
+	}
+
+	if len(structTypes) == 0 {
+		return structTypes, fmt.Errorf("no structs found")
+	}
+
+	return structTypes, nil
+}
+
+// parseDir parses the Go source files of a directory matching the build constraints of the current platform, see
+// build.Context.MatchFile, excluding test files unless there are only test files.
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the directory, error was: %v", err)
+	}
+	var sources, tests []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		// e.g. a file suffixed with _windows or a generator constrained by //go:build ignore
+		if match, err := build.Default.MatchFile(dir, name); err != nil || !match {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			tests = append(tests, filepath.Join(dir, name))
+		} else {
+			sources = append(sources, filepath.Join(dir, name))
+		}
+	}
+	if len(sources) == 0 {
+		sources = tests
+	}
+
+	var files []*ast.File
+	for _, source := range sources {
+		file, err := parser.ParseFile(fset, source, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parsed the file, error was: %v", err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// getStructsFromFiles type checks the parsed files of a package found in dir, importing its dependencies with imp, and
+// returns the structs it declares, in alphabetical order.
+func getStructsFromFiles(fset *token.FileSet, dir string, files []*ast.File, imp types.Importer) ([]StructDiscovered, error) {
+	var structTypes []StructDiscovered
+
 	// Type checks the parsed AST using types.Config.Check
 	// A Config controls various options of the type checker.
 	// The defaults work fine except for one setting:
 	// we must specify how to deal with imports.
-	conf := types.Config{Importer: importer.Default()}
-	pkg, err := conf.Check("mypkg", fset, []*ast.File{file}, nil)
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(packagePath(dir), fset, files, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to type check the file, error was: %v", err)
 	}
 
+	docs := typeDocs(files)
 	// Get the package's scope, containing package-level declarations
 	scope := pkg.Scope()
 	for _, name := range scope.Names() {
@@ -51,14 +204,137 @@ func GetStructsFromSourceFile(sourceFilePath string) ([]StructDiscovered, error)
 				var newStruct StructDiscovered
 				newStruct.Name = typeName
 				newStruct.Obj = structType
+				newStruct.Fset = fset
+				newStruct.Doc = docs[typeName.Pos()]
 				structTypes = append(structTypes, newStruct)
 			}
 		}
 	}
 
-	if len(structTypes) == 0 {
-		return structTypes, fmt.Errorf("no structs found")
+	return structTypes, nil
+}
+
+// typeDocs returns the doc comments of the type declarations of the files, by position of the declared name. The doc
+// comment of a declaration declaring a single type, e.g. type Article struct, is the one of that type.
+func typeDocs(files []*ast.File) map[token.Pos]*ast.CommentGroup {
+	docs := make(map[token.Pos]*ast.CommentGroup)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				if doc != nil {
+					docs[typeSpec.Name.Pos()] = doc
+				}
+			}
+		}
 	}
+	return docs
+}
 
-	return structTypes, nil
+// packagePath returns the import path of the package in dir, see ImportPath.
+// It returns defaultPkgPath if it cannot be found, e.g. when there is no go.mod file.
+func packagePath(dir string) string {
+	importPath, err := ImportPath(dir)
+	if err != nil {
+		return defaultPkgPath
+	}
+	return importPath
+}
+
+// ImportPath returns the import path of the package in dir, found from the module path of the nearest go.mod file,
+// the one of dir or of its closest parent directory.
+func ImportPath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for moduleDir := absDir; ; moduleDir = filepath.Dir(moduleDir) {
+		content, err := os.ReadFile(filepath.Join(moduleDir, "go.mod"))
+		if err == nil {
+			modulePath := modulePathFromGoMod(string(content))
+			if modulePath == "" {
+				return "", fmt.Errorf("no module path in %s", filepath.Join(moduleDir, "go.mod"))
+			}
+			rel, err := filepath.Rel(moduleDir, absDir)
+			if err != nil || rel == "." {
+				return modulePath, nil
+			}
+			return modulePath + "/" + filepath.ToSlash(rel), nil
+		}
+		if filepath.Dir(moduleDir) == moduleDir {
+			return "", fmt.Errorf("no go.mod file found for %s", absDir)
+		}
+	}
+}
+
+// modulePathFromGoMod returns the module path declared in the content of a go.mod file.
+func modulePathFromGoMod(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// exportImporter imports the packages listed by `go list -export` from their export data, which finds the packages
+// of the module being loaded, and the other packages with the default importer.
+type exportImporter struct {
+	exports    map[string]string
+	exportData types.Importer
+	fallback   types.Importer
+}
+
+// newImporter returns the types.Importer used to type check the packages listed by the go list patterns, e.g. package
+// directories or the files of a single package, run from dir. withTests also lists the dependencies of their tests.
+func newImporter(fset *token.FileSet, dir string, patterns []string, withTests bool) types.Importer {
+	exports := listExports(dir, patterns, withTests)
+	return &exportImporter{
+		exports: exports,
+		exportData: importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+			return os.Open(exports[path])
+		}),
+		fallback: importer.Default(),
+	}
+}
+
+func (i *exportImporter) Import(path string) (*types.Package, error) {
+	if i.exports[path] != "" {
+		return i.exportData.Import(path)
+	}
+	return i.fallback.Import(path)
+}
+
+// listExports returns the export data file of the packages listed by the go list patterns run from dir and of their
+// dependencies, by import path. It returns an empty map if the go command fails, e.g. outside of a module.
+func listExports(dir string, patterns []string, withTests bool) map[string]string {
+	exports := make(map[string]string)
+	args := []string{"list", "-e", "-export", "-deps", "-f", "{{.ImportPath}}\t{{.Export}}"}
+	if withTests {
+		args = append(args, "-test")
+	}
+	args = append(append(args, "--"), patterns...)
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return exports
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		path, export, found := strings.Cut(line, "\t")
+		if found && export != "" {
+			exports[path] = export
+		}
+	}
+	return exports
 }
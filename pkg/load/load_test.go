@@ -2,6 +2,8 @@ package load
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -32,6 +34,116 @@ func TestFindStructsInPkg(t *testing.T) {
 	}
 }
 
+func TestGetStructsFromPath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module example.com/app\n\ngo 1.22\n",
+		"models/article.go":   "package models\n\nimport \"example.com/app/users\"\n\ntype Article struct { Author users.User }\n",
+		"models/comment.go":   "package models\n\ntype Comment struct { Text string }\n",
+		"models/a_test.go":    "package models\n\ntype Fixture struct { Text string }\n",
+		"users/user.go":       "package users\n\ntype User struct { Name string }\n",
+		"testdata/ignored.go": "package testdata\n\ntype Ignored struct { Name string }\n",
+		// Only one of the files declaring Settings matches the build constraints of the platform
+		"platform/config.go":           "package platform\n\ntype Config struct { Settings Settings }\n",
+		"platform/settings_linux.go":   "package platform\n\ntype Settings struct { Linux bool }\n",
+		"platform/settings_windows.go": "package platform\n\ntype Settings struct { Windows bool }\n",
+		"platform/settings_other.go":   "//go:build !linux && !windows\n\npackage platform\n\ntype Settings struct { Other bool }\n",
+		"platform/gen.go":              "//go:build ignore\n\npackage main\n\ntype Generator struct { Out string }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tests = []struct {
+		sourcePath string
+		expected   []string
+	}{
+		{filepath.Join(dir, "models"), []string{"example.com/app/models.Article", "example.com/app/models.Comment"}},
+		{filepath.Join(dir, "models", "comment.go"), []string{"example.com/app/models.Comment"}},
+		{filepath.Join(dir, "platform"), []string{"example.com/app/platform.Config", "example.com/app/platform.Settings"}},
+		{filepath.Join(dir, "..."), []string{"example.com/app/models.Article", "example.com/app/models.Comment", "example.com/app/platform.Config", "example.com/app/platform.Settings", "example.com/app/users.User"}},
+	}
+
+	for _, testcase := range tests {
+		t.Run(testcase.sourcePath, func(t *testing.T) {
+			result, err := GetStructsFromPath(testcase.sourcePath)
+			if err != nil {
+				t.Fatalf("unexpected error '%s'", err)
+			}
+			var got []string
+			for _, structFound := range result {
+				got = append(got, structFound.Name.Pkg().Path()+"."+structFound.Name.Name())
+			}
+			if strings.Join(got, ",") != strings.Join(testcase.expected, ",") {
+				t.Errorf("expected structs %v, got %v", testcase.expected, got)
+			}
+		})
+	}
+}
+
+// TestStructDocs is a unit test for the doc comments of the structs found by GetStructsFromSourceFile.
+func TestStructDocs(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "models.go")
+	content := "package models\n\n// Article is an article.\n//gql:entity\ntype Article struct{ Title string }\n\n" +
+		"// Grouped types\ntype (\n\t// Comment is a comment.\n\tComment struct{ Text string }\n\tUser struct{ Name string }\n)\n"
+	if err := os.WriteFile(filePath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	structs, err := GetStructsFromSourceFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"Article": "// Article is an article.\n//gql:entity", "Comment": "// Comment is a comment.", "User": ""}
+	for _, structFound := range structs {
+		var doc []string
+		if structFound.Doc != nil {
+			for _, comment := range structFound.Doc.List {
+				doc = append(doc, comment.Text)
+			}
+		}
+		if got := strings.Join(doc, "\n"); got != expected[structFound.Name.Name()] {
+			t.Errorf("doc of %s = %q, expected %q", structFound.Name.Name(), got, expected[structFound.Name.Name()])
+		}
+	}
+}
+
+// TestImportPath is a unit test for the ImportPath function.
+func TestImportPath(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("// The module\nmodule \"github.com/acme/api\" // the API\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "graph", "scalars")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{name: "ModuleRoot", dir: root, want: "github.com/acme/api"},
+		{name: "Subdirectory", dir: dir, want: "github.com/acme/api/graph/scalars"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ImportPath(tt.dir)
+			if err != nil {
+				t.Fatalf("ImportPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ImportPath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	// generate test data files
 	_ = os.WriteFile("valid.go", []byte("package foo; type Bar struct { Counter int }"), 0600)
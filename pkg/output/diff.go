@@ -0,0 +1,218 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around the changes of a unified diff.
+const diffContext = 3
+
+// diffOp is the operation of a line of a diff: ' ' kept, '-' deleted or '+' inserted.
+type diffOp byte
+
+// diffLine represents a line of a diff.
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// UnifiedDiff returns the unified diff, as printed by `diff -u`, turning the content from into the content to.
+// fromName and toName are the names of the files printed in the header. It returns an empty string if the contents
+// are identical.
+func UnifiedDiff(fromName string, toName string, from string, to string) string {
+	if from == to {
+		return ""
+	}
+	lines := diffLines(splitLines(from), splitLines(to))
+
+	var diff bytes.Buffer
+	diff.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+	for start := 0; start < len(lines); {
+		// Find the next change and the end of its hunk, merging the changes separated by few unchanged lines
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		last := first
+		for i := first; i < len(lines) && i-last <= 2*diffContext; i++ {
+			if lines[i].op != ' ' {
+				last = i
+			}
+		}
+		hunkStart := max(first-diffContext, start)
+		hunkEnd := min(last+diffContext+1, len(lines))
+		writeHunk(&diff, lines, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+	return diff.String()
+}
+
+// writeHunk writes the hunk made of lines[start:end], with its header.
+func writeHunk(diff *bytes.Buffer, lines []diffLine, start int, end int) {
+	fromLine, toLine := 1, 1
+	for _, line := range lines[:start] {
+		if line.op != '+' {
+			fromLine++
+		}
+		if line.op != '-' {
+			toLine++
+		}
+	}
+	fromCount, toCount := 0, 0
+	for _, line := range lines[start:end] {
+		if line.op != '+' {
+			fromCount++
+		}
+		if line.op != '-' {
+			toCount++
+		}
+	}
+	// An empty range starts at the line before it
+	if fromCount == 0 {
+		fromLine--
+	}
+	if toCount == 0 {
+		toLine--
+	}
+	diff.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount))
+	for _, line := range lines[start:end] {
+		diff.WriteString(fmt.Sprintf("%c%s\n", line.op, line.text))
+	}
+}
+
+// splitLines splits a content into lines, without the trailing newline of the last line.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffLines returns the shortest edit script turning the lines a into the lines b, using the linear space variant of
+// Myers' algorithm: the middle snake of the edit graph splits it into two smaller graphs, diffed recursively, so that
+// the memory used grows with the number of lines rather than with its square.
+func diffLines(a []string, b []string) []diffLine {
+	d := &differ{a: a, b: b}
+	d.diff(0, len(a), 0, len(b))
+	return d.lines
+}
+
+// differ builds the edit script turning the lines a into the lines b.
+type differ struct {
+	a, b  []string
+	lines []diffLine // lines is the edit script built so far
+}
+
+// diff appends the edit script turning the lines a[aLo:aHi] into the lines b[bLo:bHi].
+func (d *differ) diff(aLo int, aHi int, bLo int, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.lines = append(d.lines, diffLine{op: ' ', text: d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	x, y := -1, -1
+	if aLo < aHi && bLo < bHi {
+		x, y = d.middleSnake(aLo, aHi, bLo, bHi)
+	}
+	if x >= 0 {
+		d.diff(aLo, x, bLo, y)
+		d.diff(x, aHi, y, bHi)
+	} else {
+		for _, text := range d.a[aLo:aHi] {
+			d.lines = append(d.lines, diffLine{op: '-', text: text})
+		}
+		for _, text := range d.b[bLo:bHi] {
+			d.lines = append(d.lines, diffLine{op: '+', text: text})
+		}
+	}
+	for _, text := range d.a[aHi : aHi+suffix] {
+		d.lines = append(d.lines, diffLine{op: ' ', text: text})
+	}
+}
+
+// middleSnake returns the point splitting the shortest edit script turning the lines a[aLo:aHi] into b[bLo:bHi],
+// found where the furthest reaching paths from both ends of the edit graph overlap, or -1, -1 if the lines have
+// nothing in common. The first and last lines of both ranges must differ.
+func (d *differ) middleSnake(aLo int, aHi int, bLo int, bHi int) (int, int) {
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// forward and backward are the furthest reaching x of the paths from the start and from the end, by diagonal
+	forward, backward := make([]int, 2*maxD+2), make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	// With an odd delta, the paths overlap on a forward step, otherwise on a backward step
+	odd := delta%2 != 0
+	// The diagonals leaving the graph are skipped
+	forwardStart, forwardEnd, backwardStart, backwardEnd := 0, 0, 0, 0
+
+	for edits := 0; edits < maxD; edits++ {
+		for k := -edits + forwardStart; k <= edits-forwardEnd; k += 2 {
+			var x int
+			if k == -edits || (k != edits && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			switch {
+			case x > n:
+				forwardEnd += 2
+			case y > m:
+				forwardStart += 2
+			case odd:
+				backwardIdx := offset + delta - k
+				if backwardIdx >= 0 && backwardIdx < len(backward) && backward[backwardIdx] != -1 && x >= n-backward[backwardIdx] {
+					return aLo + x, bLo + y
+				}
+			}
+		}
+		for k := -edits + backwardStart; k <= edits-backwardEnd; k += 2 {
+			var x int
+			if k == -edits || (k != edits && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			switch {
+			case x > n:
+				backwardEnd += 2
+			case y > m:
+				backwardStart += 2
+			case !odd:
+				forwardIdx := offset + delta - k
+				if forwardIdx >= 0 && forwardIdx < len(forward) && forward[forwardIdx] != -1 && forward[forwardIdx] >= n-x {
+					forwardX := forward[forwardIdx]
+					return aLo + forwardX, bLo + forwardX - (delta - k)
+				}
+			}
+		}
+	}
+	return -1, -1
+}
@@ -0,0 +1,156 @@
+package output
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestUnifiedDiff is a unit test for the UnifiedDiff function.
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "Identical",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "ChangedLine",
+			from: "type A {\n  a: Int\n}\n",
+			to:   "type A {\n  a: String\n}\n",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n type A {\n-  a: Int\n+  a: String\n }\n",
+		},
+		{
+			name: "NewFile",
+			from: "",
+			to:   "a\nb\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "RemovedFile",
+			from: "a\n",
+			to:   "",
+			want: "--- old\n+++ new\n@@ -1,1 +0,0 @@\n-a\n",
+		},
+		{
+			name: "SeparateHunks",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			to:   "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: "--- old\n+++ new\n@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", tt.from, tt.to); got != tt.want {
+				t.Errorf("UnifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDiffLines is a unit test for the diffLines function: the edit script turns the lines from into the lines to,
+// with as few edits as the longest common subsequence allows.
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "Empty", from: "", to: ""},
+		{name: "Inserted", from: "a c", to: "a b c"},
+		{name: "Removed", from: "a b c", to: "a c"},
+		{name: "Replaced", from: "a", to: "b"},
+		{name: "Swapped", from: "a b", to: "b a"},
+		{name: "Interleaved", from: "a b c a b b a", to: "c b a b a c"},
+		{name: "NothingInCommon", from: "a b c", to: "d e"},
+		{name: "Repeated", from: "x a a a x", to: "a x a x a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := strings.Fields(tt.from), strings.Fields(tt.to)
+			var gotFrom, gotTo []string
+			edits := 0
+			for _, line := range diffLines(from, to) {
+				if line.op != '+' {
+					gotFrom = append(gotFrom, line.text)
+				}
+				if line.op != '-' {
+					gotTo = append(gotTo, line.text)
+				}
+				if line.op != ' ' {
+					edits++
+				}
+			}
+			if strings.Join(gotFrom, " ") != tt.from || strings.Join(gotTo, " ") != tt.to {
+				t.Errorf("diffLines() turns %q into %q, want %q into %q", strings.Join(gotFrom, " "), strings.Join(gotTo, " "), tt.from, tt.to)
+			}
+			if want := len(from) + len(to) - 2*longestCommonSubsequence(from, to); edits != want {
+				t.Errorf("diffLines() makes %d edits, want %d", edits, want)
+			}
+		})
+	}
+}
+
+// TestDiffLinesMemory checks that the memory used by the diffLines function grows linearly with the number of lines,
+// by diffing files with nothing in common, the worst case.
+func TestDiffLinesMemory(t *testing.T) {
+	const lines = 5000
+	from, to := make([]string, lines), make([]string, lines)
+	for idx := range from {
+		from[idx] = fmt.Sprintf("from %d", idx)
+		to[idx] = fmt.Sprintf("to %d", idx)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	diffLines(from, to)
+	runtime.ReadMemStats(&after)
+	// The edit script alone takes 2*lines diffLine, the trace of every edit step would take lines*lines words
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16<<20 {
+		t.Errorf("diffLines() allocated %d bytes for %d lines, want at most %d", allocated, lines, 16<<20)
+	}
+}
+
+// BenchmarkUnifiedDiff measures the UnifiedDiff function on a schema file with a line changed every 10 lines.
+func BenchmarkUnifiedDiff(b *testing.B) {
+	var from, to strings.Builder
+	for idx := 0; idx < 5000; idx++ {
+		fmt.Fprintf(&from, "  field%d: Int\n", idx)
+		if idx%10 == 0 {
+			fmt.Fprintf(&to, "  field%d: String\n", idx)
+		} else {
+			fmt.Fprintf(&to, "  field%d: Int\n", idx)
+		}
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		UnifiedDiff("old", "new", from.String(), to.String())
+	}
+}
+
+// longestCommonSubsequence returns the length of the longest common subsequence of a and b.
+func longestCommonSubsequence(a []string, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	return lengths[0][0]
+}
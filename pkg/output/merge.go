@@ -0,0 +1,288 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+	"github.com/VintageOps/structogqlgen/pkg/sdl"
+)
+
+// Markers delimiting the region of a hand-written schema file holding the generated definitions
+const (
+	BeginMarker = "# structogqlgen:begin"
+	EndMarker   = "# structogqlgen:end"
+)
+
+// MergeFile merges the GqlSchema into the schema file at filePath, see MergeContent, creating the file if it does not exist.
+// It returns true if the file was written.
+func MergeFile(filePath string, schema *conversion.GqlSchema) (bool, error) {
+	existing, err := os.ReadFile(filePath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, fmt.Errorf("failed to read the schema file, error was: %v", err)
+	}
+	content, err := MergeContent(string(existing), schema)
+	if err != nil {
+		return false, fmt.Errorf("failed to merge the schema into %s: %v", filePath, err)
+	}
+	return WriteFile(filePath, content)
+}
+
+// MergeContent returns the content of the hand-written schema file existing updated with the GqlSchema, leaving the
+// rest of the content byte-for-byte unchanged:
+// - if the content has a BeginMarker line and an EndMarker line, the region between them is replaced with the scalars
+// and types of the GqlSchema
+// - otherwise, the types and scalars of the GqlSchema, identified by name and kind, e.g. input for an input object
+// type, replace their definitions in the content
+// and the ones not defined yet are appended to it
+//
+// In both cases, a type or scalar of the GqlSchema defined by hand with another kind, or outside of the markers,
+// is not generated, e.g. to write an enum by hand instead of the scalar of a named Go string. Neither is a directive
+// definition of the GqlSchema already defined by hand. An empty content is replaced with the GqlSchema between markers.
+//
+// A type extension of the GqlSchema, e.g. extend type Query, is identified by the extension of the content sharing a
+// field with it, see ownedExtensions, rather than by name: the content may define the type it extends, or extensions
+// of its own. The schema extension of the GqlSchema, e.g. the @link of a federated schema, is identified by the first
+// extend schema of the content.
+func MergeContent(existing string, schema *conversion.GqlSchema) (string, error) {
+	if strings.TrimSpace(existing) == "" {
+		return BeginMarker + "\n" + strings.TrimPrefix(schemaContent(schema), "\n") + EndMarker + "\n", nil
+	}
+
+	begin, end, err := findMarkers(existing)
+	if err != nil {
+		return "", err
+	}
+	if begin >= 0 {
+		// Only the definitions outside of the markers are hand-written
+		definitions, err := sdl.ParseDefinitions(existing[:begin] + strings.Repeat(" ", end-begin) + existing[end:])
+		if err != nil {
+			return "", err
+		}
+		remaining := withoutDefinitions(schema, definitions)
+		return existing[:begin] + strings.TrimPrefix(schemaContent(remaining), "\n") + existing[end:], nil
+	}
+
+	definitions, err := sdl.ParseDefinitions(existing)
+	if err != nil {
+		return "", err
+	}
+	typesByName := make(map[string]conversion.GqlSchemaType, len(schema.Types))
+	for _, schemaType := range schema.Types {
+		typesByName[schemaType.Name] = schemaType
+	}
+	scalarsByName := make(map[string]bool, len(schema.Scalars))
+	for _, scalar := range schema.Scalars {
+		scalarsByName[scalar.Name] = true
+	}
+
+	// Replace the definitions owned from the last to the first, so that the offsets of the others remain valid
+	merged := existing
+	extensions := ownedExtensions(schema, definitions)
+	schemaExtension := schemaExtensionIndex(definitions)
+	for idx := len(definitions) - 1; idx >= 0; idx-- {
+		definition := definitions[idx]
+		var replacement string
+		if schemaType, ok := extensions[idx]; ok {
+			replacement = strings.TrimSuffix(schemaType.String(), "\n")
+		} else if idx == schemaExtension && len(schema.SchemaDirectives) != 0 {
+			replacement = strings.TrimSuffix(conversion.SchemaExtensionString(schema.SchemaDirectives), "\n")
+		} else if definition.Extend {
+			continue
+		} else if schemaType, ok := typesByName[definition.Name]; ok && definition.Kind == definitionKind(schemaType) && !schemaType.Extend {
+			replacement = strings.TrimSuffix(schemaType.String(), "\n")
+		} else if scalarsByName[definition.Name] && definition.Kind == sdl.KindScalar {
+			replacement = strings.TrimSuffix(conversion.GqlSchemaScalar{Name: definition.Name}.String(), "\n")
+		} else {
+			continue
+		}
+		merged = merged[:definition.Start] + replacement + merged[definition.End:]
+	}
+
+	remaining := withoutDefinitions(schema, definitions)
+	if len(remaining.SchemaDirectives) == 0 && len(remaining.Directives) == 0 && len(remaining.Scalars) == 0 && len(remaining.Types) == 0 {
+		return merged, nil
+	}
+	if !strings.HasSuffix(merged, "\n") {
+		merged += "\n"
+	}
+	return merged + schemaContent(remaining), nil
+}
+
+// HandWrittenDefinitions returns the definitions of the schema file at filePath that remain hand-written once the
+// GqlSchema is merged into it with MergeFile, i.e. the ones outside of the markers, or the ones the GqlSchema does not
+// replace if there is no marker. The type extensions and the schema definitions are left out. It returns nil if the
+// file does not exist.
+func HandWrittenDefinitions(filePath string, schema *conversion.GqlSchema) (*conversion.ExternalDefinitions, error) {
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the schema file, error was: %v", err)
+	}
+	existing := string(content)
+	begin, end, err := findMarkers(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the schema file %s: %v", filePath, err)
+	}
+	replaced := make(map[string]string)
+	if begin >= 0 {
+		existing = existing[:begin] + strings.Repeat(" ", end-begin) + existing[end:]
+	} else {
+		for _, schemaType := range schema.Types {
+			if !schemaType.Extend {
+				replaced[schemaType.Name] = definitionKind(schemaType)
+			}
+		}
+		for _, scalar := range schema.Scalars {
+			replaced[scalar.Name] = sdl.KindScalar
+		}
+	}
+	definitions, err := sdl.ParseDefinitions(existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the schema file %s: %v", filePath, err)
+	}
+
+	external := &conversion.ExternalDefinitions{Types: make(map[string]string)}
+	for _, definition := range definitions {
+		switch {
+		case definition.Kind == sdl.KindDirective:
+			external.Directives = append(external.Directives, existing[definition.Start:definition.End])
+		case definition.Extend || definition.Kind == sdl.KindSchema || replaced[definition.Name] == definition.Kind:
+		default:
+			external.Types[definition.Name] = definition.Kind
+		}
+	}
+	return external, nil
+}
+
+// findMarkers returns the byte offsets of the region between the BeginMarker line and the EndMarker line of content,
+// or -1 if there is no marker. It returns an error if a marker is missing, duplicated or misplaced.
+func findMarkers(content string) (int, int, error) {
+	begin, end := -1, -1
+	for offset := 0; offset < len(content); {
+		lineEnd := strings.IndexByte(content[offset:], '\n')
+		next := offset + lineEnd + 1
+		if lineEnd < 0 {
+			next = len(content)
+		}
+		switch strings.TrimSpace(content[offset:next]) {
+		case BeginMarker:
+			if begin >= 0 {
+				return -1, -1, fmt.Errorf("more than one %q marker", BeginMarker)
+			}
+			begin = next
+		case EndMarker:
+			if end >= 0 {
+				return -1, -1, fmt.Errorf("more than one %q marker", EndMarker)
+			}
+			end = offset
+		}
+		offset = next
+	}
+	switch {
+	case begin < 0 && end < 0:
+		return -1, -1, nil
+	case begin < 0 || end < 0:
+		return -1, -1, fmt.Errorf("the %q and %q markers must be used together", BeginMarker, EndMarker)
+	case end < begin:
+		return -1, -1, fmt.Errorf("the %q marker must precede the %q marker", BeginMarker, EndMarker)
+	}
+	return begin, end, nil
+}
+
+// withoutDefinitions returns the GqlSchema without its types and scalars named like one of the definitions,
+// extensions excluded, without its type extensions owning one of the definitions, see ownedExtensions, without its
+// directive definitions named like one of the directive definitions, and without its schema extension if one of the
+// definitions extends the schema.
+func withoutDefinitions(schema *conversion.GqlSchema, definitions []sdl.Definition) *conversion.GqlSchema {
+	defined := make(map[string]bool, len(definitions))
+	directivesDefined := make(map[string]bool)
+	for _, definition := range definitions {
+		if definition.Kind == sdl.KindDirective {
+			directivesDefined[definition.Name] = true
+		} else if !definition.Extend {
+			defined[definition.Name] = true
+		}
+	}
+	extended := make(map[string]bool)
+	for _, schemaType := range ownedExtensions(schema, definitions) {
+		extended[schemaType.Name] = true
+	}
+	remaining := &conversion.GqlSchema{}
+	if schemaExtensionIndex(definitions) < 0 {
+		remaining.SchemaDirectives = schema.SchemaDirectives
+	}
+	for _, directive := range schema.Directives {
+		if !directivesDefined[directive.Name] {
+			remaining.Directives = append(remaining.Directives, directive)
+		}
+	}
+	for _, scalar := range schema.Scalars {
+		if !defined[scalar.Name] {
+			remaining.Scalars = append(remaining.Scalars, scalar)
+		}
+	}
+	for _, schemaType := range schema.Types {
+		if (schemaType.Extend && !extended[schemaType.Name]) || (!schemaType.Extend && !defined[schemaType.Name]) {
+			remaining.Types = append(remaining.Types, schemaType)
+		}
+	}
+	return remaining
+}
+
+// ownedExtensions returns the type extensions of the GqlSchema by index of the definition each owns: the first
+// extension of the same type sharing a field with it, e.g. the extend type Query written by an earlier run. The other
+// extensions are written by hand, and kept along with the ones of the GqlSchema.
+func ownedExtensions(schema *conversion.GqlSchema, definitions []sdl.Definition) map[int]conversion.GqlSchemaType {
+	owned := make(map[int]conversion.GqlSchemaType)
+	for _, schemaType := range schema.Types {
+		if !schemaType.Extend {
+			continue
+		}
+		fields := make(map[string]bool, len(schemaType.Fields))
+		for _, field := range schemaType.Fields {
+			fields[field.Name] = true
+		}
+		for idx, definition := range definitions {
+			if definition.Extend && definition.Name == schemaType.Name && definition.Kind == definitionKind(schemaType) && sharesField(definition, fields) {
+				owned[idx] = schemaType
+				break
+			}
+		}
+	}
+	return owned
+}
+
+// definitionKind returns the kind of the definition of the type, sdl.KindInput for an input object type and
+// sdl.KindType for an object type.
+func definitionKind(schemaType conversion.GqlSchemaType) string {
+	if schemaType.IsInput() {
+		return sdl.KindInput
+	}
+	return sdl.KindType
+}
+
+// schemaExtensionIndex returns the index of the first definition extending the schema, or -1 if there is none.
+func schemaExtensionIndex(definitions []sdl.Definition) int {
+	for idx, definition := range definitions {
+		if definition.Kind == sdl.KindSchema && definition.Extend {
+			return idx
+		}
+	}
+	return -1
+}
+
+// sharesField returns true if one of the fields of the definition is among the fields.
+func sharesField(definition sdl.Definition, fields map[string]bool) bool {
+	for _, field := range definition.Fields {
+		if fields[field.Name] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// TestMergeContent is a unit test for the MergeContent function.
+func TestMergeContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Empty",
+			existing: "",
+			want:     BeginMarker + "\nscalar BigInt\n\ntype Article {\n  views: BigInt\n}\n\ntype User {\n  name: String!\n}\n" + EndMarker + "\n",
+		},
+		{
+			name: "Markers",
+			existing: "type Query {\n  users: [User]\n}\n\n" + BeginMarker + "\ntype Old {\n  a: Int\n}\n" + EndMarker + "\n\n" +
+				"# The end\n",
+			want: "type Query {\n  users: [User]\n}\n\n" + BeginMarker + "\nscalar BigInt\n\ntype Article {\n  views: BigInt\n}\n\n" +
+				"type User {\n  name: String!\n}\n" + EndMarker + "\n\n# The end\n",
+		},
+		{
+			name:     "MarkersWithHandWrittenScalar",
+			existing: "enum BigInt {\n  A\n}\n" + BeginMarker + "\n" + EndMarker + "\n",
+			want: "enum BigInt {\n  A\n}\n" + BeginMarker + "\ntype Article {\n  views: BigInt\n}\n\ntype User {\n  name: String!\n}\n" +
+				EndMarker + "\n",
+		},
+		{
+			name: "OwnedTypes",
+			existing: "# Users\n\"The user\"\ntype User {\n  id: ID!\n} # kept comment\n\nextend type User {\n  friends: [User]\n}\n\n" +
+				"type Query {\n  user: User\n}",
+			want: "# Users\n\"The user\"\ntype User {\n  name: String!\n} # kept comment\n\nextend type User {\n  friends: [User]\n}\n\n" +
+				"type Query {\n  user: User\n}\n\nscalar BigInt\n\ntype Article {\n  views: BigInt\n}\n",
+		},
+		{
+			name:     "OwnedTypesHandWrittenKind",
+			existing: "scalar BigInt\ninput Article {\n  views: Int\n}\n",
+			want:     "scalar BigInt\ninput Article {\n  views: Int\n}\n\ntype User {\n  name: String!\n}\n",
+		},
+		{
+			name:     "MissingEndMarker",
+			existing: BeginMarker + "\n",
+			wantErr:  true,
+		},
+		{
+			name:     "MisplacedMarkers",
+			existing: EndMarker + "\n" + BeginMarker + "\n",
+			wantErr:  true,
+		},
+		{
+			name:     "InvalidSchema",
+			existing: "type User {\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeContent(tt.existing, testSchema())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MergeContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MergeContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergeContentDirectives checks that the directive definitions already defined by hand are not generated.
+func TestMergeContentDirectives(t *testing.T) {
+	schema := testSchema()
+	schema.Directives = []conversion.GqlSchemaDirectiveDefinition{
+		{Name: "goModel", Definition: "directive @goModel(model: String) on OBJECT"},
+		{Name: "goField", Definition: "directive @goField(name: String) on FIELD_DEFINITION"},
+	}
+	existing := "directive @goModel(model: String, models: [String!]) on OBJECT | INPUT_OBJECT\n" + BeginMarker + "\n" + EndMarker + "\n"
+	want := "directive @goModel(model: String, models: [String!]) on OBJECT | INPUT_OBJECT\n" + BeginMarker + "\n" +
+		"directive @goField(name: String) on FIELD_DEFINITION\n\nscalar BigInt\n\ntype Article {\n  views: BigInt\n}\n\n" +
+		"type User {\n  name: String!\n}\n" + EndMarker + "\n"
+	got, err := MergeContent(existing, schema)
+	if err != nil {
+		t.Fatalf("MergeContent() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("MergeContent() = %q, want %q", got, want)
+	}
+}
+
+// TestMergeContentExtensions checks that a type extension replaces the extension sharing a field with it only.
+func TestMergeContentExtensions(t *testing.T) {
+	schema := &conversion.GqlSchema{Types: []conversion.GqlSchemaType{{Name: "Query", Extend: true, Fields: []conversion.GqlSchemaField{
+		{Name: "article", Type: "Article", Arguments: []conversion.GqlSchemaArgument{{Name: "id", Type: "ID!"}}},
+	}}}}
+	generated := "extend type Query {\n  article(id: ID!): Article\n}"
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name:     "HandWrittenType",
+			existing: "type Query {\n  me: User\n}\n",
+			want:     "type Query {\n  me: User\n}\n\n" + generated + "\n",
+		},
+		{
+			name:     "HandWrittenExtension",
+			existing: "extend type Query {\n  me: User\n}\n",
+			want:     "extend type Query {\n  me: User\n}\n\n" + generated + "\n",
+		},
+		{
+			name:     "OwnedExtension",
+			existing: "extend type Query {\n  me: User\n}\n\nextend type Query {\n  article: Article\n  articles: [Article]\n}\n",
+			want:     "extend type Query {\n  me: User\n}\n\n" + generated + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeContent(tt.existing, schema)
+			if err != nil {
+				t.Fatalf("MergeContent() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MergeContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergeContentSchemaExtension checks that the schema extension replaces the first extend schema of the content.
+func TestMergeContentSchemaExtension(t *testing.T) {
+	schema := &conversion.GqlSchema{
+		SchemaDirectives: []conversion.GqlSchemaDirective{{Name: "link", Arguments: []conversion.GqlSchemaDirectiveArg{{Name: "url", Value: `"v2.3"`}}}},
+		Types:            []conversion.GqlSchemaType{{Name: "User", Fields: []conversion.GqlSchemaField{{Name: "name", Type: "String"}}}},
+	}
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name:     "Appended",
+			existing: "type Query {\n  me: User\n}\n",
+			want:     "type Query {\n  me: User\n}\n\nextend schema @link(url: \"v2.3\")\n\ntype User {\n  name: String\n}\n",
+		},
+		{
+			name:     "Replaced",
+			existing: "extend schema @link(url: \"v2.0\")\n\ntype User {\n  id: ID\n}\n",
+			want:     "extend schema @link(url: \"v2.3\")\n\ntype User {\n  name: String\n}\n",
+		},
+		{
+			name:     "HandWrittenOutsideMarkers",
+			existing: "extend schema @link(url: \"v2.0\")\n" + BeginMarker + "\n" + EndMarker + "\n",
+			want:     "extend schema @link(url: \"v2.0\")\n" + BeginMarker + "\ntype User {\n  name: String\n}\n" + EndMarker + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergeContent(tt.existing, schema)
+			if err != nil {
+				t.Fatalf("MergeContent() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MergeContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergeFile is a unit test for the MergeFile function.
+func TestMergeFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "schema.graphqls")
+	written, err := MergeFile(filePath, testSchema())
+	if err != nil || !written {
+		t.Fatalf("MergeFile() = %v, %v, want the file written", written, err)
+	}
+
+	// Merging again keeps the content of the file
+	content, _ := os.ReadFile(filePath)
+	if err := os.WriteFile(filePath, append([]byte("type Query {\n  a: Int\n}\n"), content...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	written, err = MergeFile(filePath, testSchema())
+	if err != nil || written {
+		t.Errorf("MergeFile() = %v, %v, want the file unchanged", written, err)
+	}
+}
+
+// TestHandWrittenDefinitions is a unit test for the HandWrittenDefinitions function.
+func TestHandWrittenDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	handWritten := "directive @auth(requires: Role!) on OBJECT\n\nenum Role {\n  ADMIN\n}\n\nextend type Query {\n  me: User\n}\n\n" +
+		"type User {\n  id: ID!\n}\n\nschema {\n  query: Query\n}\n"
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			// The generated User type replaces the hand-written one
+			name:     "NoMarker",
+			existing: handWritten,
+			want:     "[directive @auth(requires: Role!) on OBJECT] map[Role:enum]",
+		},
+		{
+			// The definitions between the markers were generated
+			name:     "Markers",
+			existing: handWritten + BeginMarker + "\ntype Article {\n  views: BigInt\n}\n" + EndMarker + "\n",
+			want:     "[directive @auth(requires: Role!) on OBJECT] map[Role:enum User:type]",
+		},
+		{name: "Missing", want: "<nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(dir, tt.name+".graphqls")
+			if tt.existing != "" {
+				if err := os.WriteFile(filePath, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			external, err := HandWrittenDefinitions(filePath, testSchema())
+			if err != nil {
+				t.Fatalf("HandWrittenDefinitions() error = %v", err)
+			}
+			got := "<nil>"
+			if external != nil {
+				got = fmt.Sprintf("%v %v", external.Directives, external.Types)
+			}
+			if got != tt.want {
+				t.Errorf("HandWrittenDefinitions() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergeContentOperations is a unit test for MergeContent merging the operations of an entity twice without markers,
+// the entity changing in between: its object and input types are both replaced.
+func TestMergeContentOperations(t *testing.T) {
+	schema := func(fields ...string) *conversion.GqlSchema {
+		var fieldDefs []conversion.GqlFieldsDefinition
+		for _, field := range fields {
+			fieldDefs = append(fieldDefs, conversion.GqlFieldsDefinition{GqlFieldName: field, GqlFieldType: "String"})
+		}
+		gqlTypeDefs := []conversion.GqlTypeDefinition{{GqlTypeName: "Article", GqlTypeDirectives: []string{conversion.EntityDocDirective}, GqlFields: fieldDefs}}
+		schema, err := conversion.ResolveGqlSchema(gqlTypeDefs, &conversion.PrettyPrintOptions{Operations: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return schema
+	}
+
+	content := "type Query {\n  hello: String\n}\n"
+	var err error
+	for _, fields := range [][]string{{"Title"}, {"Title", "Body"}} {
+		if content, err = MergeContent(content, schema(fields...)); err != nil {
+			t.Fatalf("MergeContent() error = %v", err)
+		}
+	}
+	for _, want := range []string{"type Article {\n  Title: String\n  Body: String\n}", "input ArticleInput {\n  Title: String\n  Body: String\n}"} {
+		if strings.Count(content, want) != 1 {
+			t.Errorf("MergeContent() = %q, want a single %q", content, want)
+		}
+	}
+	if strings.Count(content, "input ArticleInput") != 1 {
+		t.Errorf("MergeContent() = %q, want a single ArticleInput", content)
+	}
+}
@@ -0,0 +1,266 @@
+// Package output provides functionalities to write the GraphQL schema built with package
+// github.com/VintageOps/structogqlgen/pkg/conversion into .graphqls files, as used by gqlgen's schema option.
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// GeneratedHeader is the first line of every file written, it identifies the files this tool generated.
+const GeneratedHeader = "# Code generated by structogqlgen. DO NOT EDIT."
+
+// Split modes of the schema into files
+const (
+	// SplitNone writes the whole schema into a single file
+	SplitNone = "none"
+	// SplitScalars writes the types into schema.graphqls and the custom scalars into scalars.graphqls
+	SplitScalars = "scalars"
+	// SplitPackage writes the types into one file per Go package and the custom scalars into scalars.graphqls
+	SplitPackage = "package"
+	// SplitType writes each type into its own file and the custom scalars into scalars.graphqls
+	SplitType = "type"
+)
+
+// Names of the files written when the schema is split
+const (
+	SchemaFileName     = "schema.graphqls"
+	ScalarsFileName    = "scalars.graphqls"
+	DirectivesFileName = "directives.graphqls"
+)
+
+// File represents a file to write.
+type File struct {
+	Name    string // Name is the name of the file, relative to the output directory
+	Content string // Content is the content of the file, including the GeneratedHeader
+}
+
+// WriteReport reports the changes made to the files of an output directory.
+type WriteReport struct {
+	Written   []string // Written are the paths of the files created or updated
+	Unchanged []string // Unchanged are the paths of the files that already had the expected content
+	Removed   []string // Removed are the paths of the stale files generated earlier that were removed
+}
+
+// SplitSchema splits the GqlSchema into the files to write according to the split mode. When the schema is split, the
+// schema extension and the directive definitions, if any, are written into directives.graphqls.
+func SplitSchema(schema *conversion.GqlSchema, split string) ([]File, error) {
+	switch split {
+	case "", SplitNone:
+		return []File{newFile(SchemaFileName, schema)}, nil
+	case SplitScalars:
+		return withScalarsFile(schema, []File{newFile(SchemaFileName, &conversion.GqlSchema{Types: schema.Types})}), nil
+	case SplitPackage:
+		var files []File
+		typesByPackage := make(map[string][]conversion.GqlSchemaType)
+		var packages []string
+		for _, schemaType := range schema.Types {
+			if _, ok := typesByPackage[schemaType.Package]; !ok {
+				packages = append(packages, schemaType.Package)
+			}
+			typesByPackage[schemaType.Package] = append(typesByPackage[schemaType.Package], schemaType)
+		}
+		fileNames := packageFileNames(packages)
+		for _, pkgPath := range packages {
+			files = append(files, newFile(fileNames[pkgPath], &conversion.GqlSchema{Types: typesByPackage[pkgPath]}))
+		}
+		return withScalarsFile(schema, files), nil
+	case SplitType:
+		var files []File
+		for _, schemaType := range schema.Types {
+			files = append(files, newFile(schemaType.Name+".graphqls", &conversion.GqlSchema{Types: []conversion.GqlSchemaType{schemaType}}))
+		}
+		return withScalarsFile(schema, files), nil
+	default:
+		return nil, ValidateSplit(split)
+	}
+}
+
+// ValidateSplit returns an error if the split mode is not valid.
+func ValidateSplit(split string) error {
+	switch split {
+	case "", SplitNone, SplitScalars, SplitPackage, SplitType:
+		return nil
+	}
+	return fmt.Errorf("invalid split mode %q, expected %s, %s, %s or %s", split, SplitNone, SplitScalars, SplitPackage, SplitType)
+}
+
+// withScalarsFile appends the file of the custom scalars to files, if there is any scalar, and the file of the
+// directive definitions, if there is any directive, along with the schema extension, if any.
+func withScalarsFile(schema *conversion.GqlSchema, files []File) []File {
+	if len(schema.Scalars) != 0 {
+		files = append(files, newFile(ScalarsFileName, &conversion.GqlSchema{Scalars: schema.Scalars}))
+	}
+	if len(schema.Directives) != 0 || len(schema.SchemaDirectives) != 0 {
+		files = append(files, newFile(DirectivesFileName, &conversion.GqlSchema{SchemaDirectives: schema.SchemaDirectives, Directives: schema.Directives}))
+	}
+	return files
+}
+
+// packageFileNames returns the file name of each package: the last element of its path, or the whole path if that
+// last element is shared with another package.
+func packageFileNames(packages []string) map[string]string {
+	baseCount := make(map[string]int)
+	for _, pkgPath := range packages {
+		baseCount[path.Base(pkgPath)]++
+	}
+	fileNames := make(map[string]string, len(packages))
+	for _, pkgPath := range packages {
+		name := path.Base(pkgPath)
+		if baseCount[name] > 1 {
+			name = strings.ReplaceAll(pkgPath, "/", "_")
+		}
+		if pkgPath == "" || name == "." || name == "/" {
+			name = "schema"
+		}
+		fileNames[pkgPath] = name + ".graphqls"
+	}
+	return fileNames
+}
+
+// newFile returns the File with the given name containing the GqlSchema.
+func newFile(name string, schema *conversion.GqlSchema) File {
+	return File{Name: name, Content: FileContent(schema)}
+}
+
+// FileContent returns the content of a file holding the GqlSchema: the GeneratedHeader, the schema extension, the
+// directive definitions, the scalars and the types, separated by blank lines.
+func FileContent(schema *conversion.GqlSchema) string {
+	return GeneratedHeader + "\n" + schemaContent(schema)
+}
+
+// schemaContent returns the schema extension, the directive definitions, the scalars and the types of the GqlSchema,
+// each group preceded by a blank line.
+func schemaContent(schema *conversion.GqlSchema) string {
+	var content bytes.Buffer
+	if len(schema.SchemaDirectives) != 0 {
+		content.WriteString("\n")
+		content.WriteString(conversion.SchemaExtensionString(schema.SchemaDirectives))
+	}
+	if len(schema.Directives) != 0 {
+		content.WriteString("\n")
+		for _, directive := range schema.Directives {
+			content.WriteString(directive.String())
+		}
+	}
+	if len(schema.Scalars) != 0 {
+		content.WriteString("\n")
+		for _, scalar := range schema.Scalars {
+			content.WriteString(scalar.String())
+		}
+	}
+	for _, schemaType := range schema.Types {
+		content.WriteString("\n")
+		content.WriteString(schemaType.String())
+	}
+	return content.String()
+}
+
+// WriteFile writes the content into the file at filePath, unless the file already has that content.
+// It returns true if the file was written.
+func WriteFile(filePath string, content string) (bool, error) {
+	existing, err := os.ReadFile(filePath)
+	if err == nil && string(existing) == content {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create the output directory, error was: %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write the file, error was: %v", err)
+	}
+	return true, nil
+}
+
+// WriteFiles writes the files into the directory dir, only rewriting the files whose content changed, and removes
+// the files of that directory that this tool generated earlier but are not part of files anymore.
+func WriteFiles(dir string, files []File) (*WriteReport, error) {
+	report := &WriteReport{}
+	for _, file := range files {
+		filePath := filepath.Join(dir, file.Name)
+		written, err := WriteFile(filePath, file.Content)
+		if err != nil {
+			return report, err
+		}
+		if written {
+			report.Written = append(report.Written, filePath)
+		} else {
+			report.Unchanged = append(report.Unchanged, filePath)
+		}
+	}
+
+	staleFiles, err := StaleFiles(dir, files)
+	if err != nil {
+		return report, err
+	}
+	for _, filePath := range staleFiles {
+		if err := os.Remove(filePath); err != nil {
+			return report, fmt.Errorf("failed to remove the stale file, error was: %v", err)
+		}
+		report.Removed = append(report.Removed, filePath)
+	}
+	return report, nil
+}
+
+// StaleFiles returns the paths of the files of the directory dir that this tool generated earlier but are not part
+// of files anymore, sorted by name. It returns no path if the directory does not exist.
+func StaleFiles(dir string, files []File) ([]string, error) {
+	expected := make(map[string]bool, len(files))
+	for _, file := range files {
+		expected[file.Name] = true
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the output directory, error was: %v", err)
+	}
+	var staleFiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || expected[name] || !isSchemaFileName(name) {
+			continue
+		}
+		filePath := filepath.Join(dir, name)
+		generated, err := IsGeneratedFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if generated {
+			staleFiles = append(staleFiles, filePath)
+		}
+	}
+	sort.Strings(staleFiles)
+	return staleFiles, nil
+}
+
+// isSchemaFileName returns true if the file name has a GraphQL schema extension.
+func isSchemaFileName(name string) bool {
+	return strings.HasSuffix(name, ".graphqls") || strings.HasSuffix(name, ".graphql")
+}
+
+// IsGeneratedFile returns true if the first line of the file at filePath is the GeneratedHeader.
+func IsGeneratedFile(filePath string) (bool, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open the file, error was: %v", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+	return strings.TrimRight(scanner.Text(), "\r") == GeneratedHeader, nil
+}
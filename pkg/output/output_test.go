@@ -0,0 +1,129 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// testSchema returns a schema with two types from two packages and a scalar.
+func testSchema() *conversion.GqlSchema {
+	return &conversion.GqlSchema{
+		Scalars: []conversion.GqlSchemaScalar{{Name: "BigInt"}},
+		Types: []conversion.GqlSchemaType{
+			{Name: "Article", Package: "example.com/app/models", Fields: []conversion.GqlSchemaField{{Name: "views", Type: "BigInt"}}},
+			{Name: "User", Package: "example.com/app/users", Fields: []conversion.GqlSchemaField{{Name: "name", Type: "String", NonNull: true}}},
+		},
+	}
+}
+
+// TestSplitSchema is a unit test for the SplitSchema function.
+func TestSplitSchema(t *testing.T) {
+	tests := []struct {
+		split     string
+		wantFiles []string
+		wantErr   bool
+	}{
+		{split: SplitNone, wantFiles: []string{"schema.graphqls"}},
+		{split: SplitScalars, wantFiles: []string{"schema.graphqls", "scalars.graphqls"}},
+		{split: SplitPackage, wantFiles: []string{"models.graphqls", "users.graphqls", "scalars.graphqls"}},
+		{split: SplitType, wantFiles: []string{"Article.graphqls", "User.graphqls", "scalars.graphqls"}},
+		{split: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.split, func(t *testing.T) {
+			files, err := SplitSchema(testSchema(), tt.split)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var gotFiles []string
+			for _, file := range files {
+				gotFiles = append(gotFiles, file.Name)
+				if !strings.HasPrefix(file.Content, GeneratedHeader+"\n") {
+					t.Errorf("SplitSchema() file %s has no header", file.Name)
+				}
+			}
+			if strings.Join(gotFiles, ",") != strings.Join(tt.wantFiles, ",") {
+				t.Errorf("SplitSchema() files = %v, want %v", gotFiles, tt.wantFiles)
+			}
+		})
+	}
+}
+
+// TestSplitSchemaDirectives checks that the directive definitions are written into their own file when the schema is split.
+func TestSplitSchemaDirectives(t *testing.T) {
+	schema := testSchema()
+	schema.Directives = []conversion.GqlSchemaDirectiveDefinition{{Name: "goModel", Definition: "directive @goModel(model: String) on OBJECT"}}
+	files, err := SplitSchema(schema, SplitScalars)
+	if err != nil {
+		t.Fatalf("SplitSchema() error = %v", err)
+	}
+	want := GeneratedHeader + "\n\ndirective @goModel(model: String) on OBJECT\n"
+	if len(files) != 3 || files[2].Name != DirectivesFileName || files[2].Content != want {
+		t.Errorf("SplitSchema() files = %+v, want the directives in %s", files, DirectivesFileName)
+	}
+}
+
+// TestSplitSchemaExtension checks that the schema extension is written into the file of the directives when the schema is split.
+func TestSplitSchemaExtension(t *testing.T) {
+	schema := testSchema()
+	schema.SchemaDirectives = []conversion.GqlSchemaDirective{{Name: "link", Arguments: []conversion.GqlSchemaDirectiveArg{{Name: "url", Value: `"v2.3"`}}}}
+	files, err := SplitSchema(schema, SplitScalars)
+	if err != nil {
+		t.Fatalf("SplitSchema() error = %v", err)
+	}
+	want := GeneratedHeader + "\n\nextend schema @link(url: \"v2.3\")\n"
+	if len(files) != 3 || files[2].Name != DirectivesFileName || files[2].Content != want {
+		t.Errorf("SplitSchema() files = %+v, want the schema extension in %s", files, DirectivesFileName)
+	}
+}
+
+// TestFileContent is a unit test for the FileContent function.
+func TestFileContent(t *testing.T) {
+	want := GeneratedHeader + "\n\nscalar BigInt\n\ntype Article {\n  views: BigInt\n}\n\ntype User {\n  name: String!\n}\n"
+	if got := FileContent(testSchema()); got != want {
+		t.Errorf("FileContent() = %q, want %q", got, want)
+	}
+}
+
+// TestWriteFiles checks that WriteFiles only rewrites changed files and removes the stale generated files.
+func TestWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	handWritten := filepath.Join(dir, "queries.graphqls")
+	if err := os.WriteFile(handWritten, []byte("type Query {\n  ok: Boolean\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := SplitSchema(testSchema(), SplitType)
+	report, err := WriteFiles(dir, files)
+	if err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	if len(report.Written) != 3 || len(report.Removed) != 0 {
+		t.Errorf("WriteFiles() first report = %+v", report)
+	}
+
+	files, _ = SplitSchema(testSchema(), SplitScalars)
+	report, err = WriteFiles(dir, files)
+	if err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	if len(report.Written) != 1 || len(report.Unchanged) != 1 || len(report.Removed) != 2 {
+		t.Errorf("WriteFiles() second report = %+v", report)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "queries.graphqls,scalars.graphqls,schema.graphqls" {
+		t.Errorf("WriteFiles() left %v", names)
+	}
+}
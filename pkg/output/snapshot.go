@@ -0,0 +1,241 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+	"github.com/VintageOps/structogqlgen/pkg/sdl"
+)
+
+// SnapshotExt is the extension of the snapshot files, which hold a GqlSchema in JSON.
+const SnapshotExt = ".json"
+
+// SnapshotVersion is the version of the format of the snapshot files written by WriteSnapshot. It is increased when
+// the format changes incompatibly, so that ReadSchema rejects the snapshots it cannot read rather than misreading them.
+const SnapshotVersion = 1
+
+// snapshot is the format of the snapshot files: the parts of a GqlSchema its comparison and printing rely on, with
+// stable JSON names, independent of the Go fields of the GqlSchema.
+type snapshot struct {
+	Version          int                  `json:"version"`
+	SchemaDirectives []snapshotDirective  `json:"schemaDirectives,omitempty"`
+	Directives       []snapshotDefinition `json:"directives,omitempty"`
+	Scalars          []string             `json:"scalars"`
+	Types            []snapshotType       `json:"types"`
+}
+
+// snapshotDefinition is the definition of a directive in a snapshot.
+type snapshotDefinition struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// snapshotType is a type in a snapshot.
+type snapshotType struct {
+	Name       string              `json:"name"`
+	Kind       string              `json:"kind"`
+	Extend     bool                `json:"extend,omitempty"`
+	Directives []snapshotDirective `json:"directives,omitempty"`
+	Fields     []snapshotField     `json:"fields"`
+}
+
+// snapshotField is a field in a snapshot, Type being its type reference with the non-null mark, e.g. [String]!.
+type snapshotField struct {
+	Name       string              `json:"name"`
+	Type       string              `json:"type"`
+	Arguments  []snapshotArgument  `json:"arguments,omitempty"`
+	Directives []snapshotDirective `json:"directives,omitempty"`
+}
+
+// snapshotArgument is an argument of a field in a snapshot.
+type snapshotArgument struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	DefaultValue string `json:"defaultValue,omitempty"`
+}
+
+// snapshotDirective is a directive applied in a snapshot, the values of its arguments being GraphQL literals.
+type snapshotDirective struct {
+	Name      string                 `json:"name"`
+	Arguments []snapshotDirectiveArg `json:"arguments,omitempty"`
+}
+
+// snapshotDirectiveArg is an argument of a directive applied in a snapshot.
+type snapshotDirectiveArg struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// WriteSnapshot writes the GqlSchema into the snapshot file at filePath, e.g. to compare a later version with it.
+// The snapshot is in the format of SnapshotVersion.
+func WriteSnapshot(filePath string, schema *conversion.GqlSchema) error {
+	content, err := json.MarshalIndent(newSnapshot(schema), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the snapshot, error was: %v", err)
+	}
+	if _, err := WriteFile(filePath, string(content)+"\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// newSnapshot returns the snapshot of the GqlSchema.
+func newSnapshot(schema *conversion.GqlSchema) snapshot {
+	s := snapshot{
+		Version:          SnapshotVersion,
+		SchemaDirectives: snapshotDirectives(schema.SchemaDirectives),
+		Scalars:          make([]string, 0, len(schema.Scalars)),
+		Types:            make([]snapshotType, 0, len(schema.Types)),
+	}
+	for _, definition := range schema.Directives {
+		s.Directives = append(s.Directives, snapshotDefinition{Name: definition.Name, Definition: definition.Definition})
+	}
+	for _, scalar := range schema.Scalars {
+		s.Scalars = append(s.Scalars, scalar.Name)
+	}
+	for _, schemaType := range schema.Types {
+		kind := schemaType.Kind
+		if kind == "" {
+			kind = conversion.KindObject
+		}
+		snapshotType := snapshotType{
+			Name:       schemaType.Name,
+			Kind:       kind,
+			Extend:     schemaType.Extend,
+			Directives: snapshotDirectives(schemaType.Directives),
+			Fields:     make([]snapshotField, 0, len(schemaType.Fields)),
+		}
+		for _, field := range schemaType.Fields {
+			typeRef := field.Type
+			if field.NonNull {
+				typeRef += "!"
+			}
+			snapshotField := snapshotField{Name: field.Name, Type: typeRef, Directives: snapshotDirectives(field.Directives)}
+			for _, arg := range field.Arguments {
+				snapshotField.Arguments = append(snapshotField.Arguments, snapshotArgument{Name: arg.Name, Type: arg.Type, DefaultValue: arg.DefaultValue})
+			}
+			snapshotType.Fields = append(snapshotType.Fields, snapshotField)
+		}
+		s.Types = append(s.Types, snapshotType)
+	}
+	return s
+}
+
+// snapshotDirectives returns the snapshot of the directives applied.
+func snapshotDirectives(directives []conversion.GqlSchemaDirective) []snapshotDirective {
+	var snapshots []snapshotDirective
+	for _, directive := range directives {
+		snapshot := snapshotDirective{Name: directive.Name}
+		for _, arg := range directive.Arguments {
+			snapshot.Arguments = append(snapshot.Arguments, snapshotDirectiveArg{Name: arg.Name, Value: arg.Value})
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// schema returns the GqlSchema of the snapshot.
+func (s snapshot) schema() *conversion.GqlSchema {
+	schema := &conversion.GqlSchema{SchemaDirectives: schemaDirectives(s.SchemaDirectives)}
+	for _, definition := range s.Directives {
+		schema.Directives = append(schema.Directives, conversion.GqlSchemaDirectiveDefinition{Name: definition.Name, Definition: definition.Definition})
+	}
+	for _, scalar := range s.Scalars {
+		schema.Scalars = append(schema.Scalars, conversion.GqlSchemaScalar{Name: scalar})
+	}
+	for _, snapshotType := range s.Types {
+		schemaType := conversion.GqlSchemaType{
+			Name:       snapshotType.Name,
+			Kind:       snapshotType.Kind,
+			Extend:     snapshotType.Extend,
+			Directives: schemaDirectives(snapshotType.Directives),
+		}
+		for _, snapshotField := range snapshotType.Fields {
+			field := conversion.GqlSchemaField{
+				Name:       snapshotField.Name,
+				Type:       strings.TrimSuffix(snapshotField.Type, "!"),
+				NonNull:    strings.HasSuffix(snapshotField.Type, "!"),
+				Directives: schemaDirectives(snapshotField.Directives),
+			}
+			for _, arg := range snapshotField.Arguments {
+				field.Arguments = append(field.Arguments, conversion.GqlSchemaArgument{Name: arg.Name, Type: arg.Type, DefaultValue: arg.DefaultValue})
+			}
+			schemaType.Fields = append(schemaType.Fields, field)
+		}
+		schema.Types = append(schema.Types, schemaType)
+	}
+	return schema
+}
+
+// schemaDirectives returns the directives applied of their snapshot.
+func schemaDirectives(snapshots []snapshotDirective) []conversion.GqlSchemaDirective {
+	var directives []conversion.GqlSchemaDirective
+	for _, snapshot := range snapshots {
+		directive := conversion.GqlSchemaDirective{Name: snapshot.Name}
+		for _, arg := range snapshot.Arguments {
+			directive.Arguments = append(directive.Arguments, conversion.GqlSchemaDirectiveArg{Name: arg.Name, Value: arg.Value})
+		}
+		directives = append(directives, directive)
+	}
+	return directives
+}
+
+// ReadSchema reads the GqlSchema at schemaPath, which is either a snapshot file written with WriteSnapshot, a schema
+// file, or a directory whose .graphqls and .graphql files hold the schema. A snapshot of another version than
+// SnapshotVersion is rejected.
+func ReadSchema(schemaPath string) (*conversion.GqlSchema, error) {
+	info, err := os.Stat(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the schema, error was: %v", err)
+	}
+
+	if strings.HasSuffix(schemaPath, SnapshotExt) && !info.IsDir() {
+		content, err := os.ReadFile(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the snapshot file, error was: %v", err)
+		}
+		var s snapshot
+		if err := json.Unmarshal(content, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse the snapshot file %s, error was: %v", schemaPath, err)
+		}
+		if s.Version != SnapshotVersion {
+			return nil, fmt.Errorf("snapshot file %s is of version %d, expected version %d, write it again with --save-snapshot", schemaPath, s.Version, SnapshotVersion)
+		}
+		return s.schema(), nil
+	}
+
+	filePaths := []string{schemaPath}
+	if info.IsDir() {
+		entries, err := os.ReadDir(schemaPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the schema directory, error was: %v", err)
+		}
+		filePaths = nil
+		for _, entry := range entries {
+			if !entry.IsDir() && isSchemaFileName(entry.Name()) {
+				filePaths = append(filePaths, filepath.Join(schemaPath, entry.Name()))
+			}
+		}
+		sort.Strings(filePaths)
+	}
+	var content strings.Builder
+	for _, filePath := range filePaths {
+		fileContent, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the schema file, error was: %v", err)
+		}
+		// Parse each file on its own to report the errors at their position in the file
+		if _, err := sdl.ParseDefinitions(string(fileContent)); err != nil {
+			return nil, fmt.Errorf("failed to parse the schema file %s: %v", filePath, err)
+		}
+		content.Write(fileContent)
+		content.WriteString("\n")
+	}
+	// The type extensions may be in another file than the type they extend
+	return sdl.ParseSchema(content.String())
+}
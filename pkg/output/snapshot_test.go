@@ -0,0 +1,166 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// TestReadSchema is a unit test for the ReadSchema function, reading the schema files and snapshots written earlier.
+func TestReadSchema(t *testing.T) {
+	dir := t.TempDir()
+	want := testSchema().String()
+
+	files, _ := SplitSchema(testSchema(), SplitType)
+	if _, err := WriteFiles(filepath.Join(dir, "split"), files); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteFile(filepath.Join(dir, "schema.graphqls"), FileContent(testSchema())); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSnapshot(filepath.Join(dir, "snapshot.json"), testSchema()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "invalid.graphqls"), []byte("type A {\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A snapshot without version, e.g. a GqlSchema encoded as is, is rejected rather than read as an empty schema
+	if err := os.WriteFile(filepath.Join(dir, "unversioned.json"), []byte(`{"Types": [{"Name": "User"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "File", path: "schema.graphqls"},
+		{name: "Directory", path: "split"},
+		{name: "Snapshot", path: "snapshot.json"},
+		{name: "Invalid", path: "invalid.graphqls", wantErr: true},
+		{name: "Unversioned", path: "unversioned.json", wantErr: true},
+		{name: "Missing", path: "missing.graphqls", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ReadSchema(filepath.Join(dir, tt.path))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && schema.String() != want {
+				t.Errorf("ReadSchema() = %q, want %q", schema.String(), want)
+			}
+		})
+	}
+}
+
+// TestWriteSnapshot is a unit test for the WriteSnapshot function, checking the format of the snapshot and that the
+// arguments and directives read back.
+func TestWriteSnapshot(t *testing.T) {
+	schema := &conversion.GqlSchema{
+		Types: []conversion.GqlSchemaType{{
+			Name:       "Query",
+			Extend:     true,
+			Directives: []conversion.GqlSchemaDirective{{Name: "auth", Arguments: []conversion.GqlSchemaDirectiveArg{{Name: "requires", Value: "ADMIN"}}}},
+			Fields: []conversion.GqlSchemaField{{
+				Name:      "articles",
+				Type:      "[Article!]",
+				NonNull:   true,
+				Arguments: []conversion.GqlSchemaArgument{{Name: "first", Type: "Int", DefaultValue: "10"}, {Name: "after", Type: "String"}},
+			}},
+		}},
+	}
+	want := `{
+  "version": 1,
+  "scalars": [],
+  "types": [
+    {
+      "name": "Query",
+      "kind": "type",
+      "extend": true,
+      "directives": [
+        {
+          "name": "auth",
+          "arguments": [
+            {
+              "name": "requires",
+              "value": "ADMIN"
+            }
+          ]
+        }
+      ],
+      "fields": [
+        {
+          "name": "articles",
+          "type": "[Article!]!",
+          "arguments": [
+            {
+              "name": "first",
+              "type": "Int",
+              "defaultValue": "10"
+            },
+            {
+              "name": "after",
+              "type": "String"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+
+	filePath := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := WriteSnapshot(filePath, schema); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != want {
+		t.Errorf("WriteSnapshot() =\n%s\nwant\n%s", content, want)
+	}
+	read, err := ReadSchema(filePath)
+	if err != nil {
+		t.Fatalf("ReadSchema() error = %v", err)
+	}
+	if read.String() != schema.String() {
+		t.Errorf("ReadSchema() = %q, want %q", read.String(), schema.String())
+	}
+}
+
+// TestReadSchemaMerged checks that a schema merged into a file defining the root operation types by hand compares
+// without change with the schema it was merged from.
+func TestReadSchemaMerged(t *testing.T) {
+	gqlTypeDefs := []conversion.GqlTypeDefinition{{GqlTypeName: "Article", GqlTypeDirectives: []string{conversion.EntityDocDirective}, GqlFields: []conversion.GqlFieldsDefinition{
+		{GqlFieldName: "Title", GqlFieldType: "String"},
+	}}}
+	schema, err := conversion.ResolveGqlSchema(gqlTypeDefs, &conversion.PrettyPrintOptions{Operations: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, existing := range []string{
+		"type Query {\n  hello: String\n}\n\ntype Mutation {\n  noop: Boolean\n}\n",
+		"type Query {\n  hello: String\n}\n\ntype Mutation {\n  noop: Boolean\n}\n\n" + BeginMarker + "\n" + EndMarker + "\n",
+	} {
+		filePath := filepath.Join(t.TempDir(), "schema.graphqls")
+		if err := os.WriteFile(filePath, []byte(existing), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := MergeFile(filePath, schema); err != nil {
+			t.Fatal(err)
+		}
+		previous, err := ReadSchema(filePath)
+		if err != nil {
+			t.Fatalf("ReadSchema() error = %v", err)
+		}
+		if changes := conversion.CompareSchemas(previous, schema); len(changes) != 0 {
+			t.Errorf("CompareSchemas() of the merged file = %v, want no change", changes)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package revision provides functionalities to extract the files of a git revision from the local object database,
+// e.g. to build the GraphQL schema of the Go structs as they were at that revision.
+package revision
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Tree represents the files of a git revision extracted into a temporary directory.
+type Tree struct {
+	Rev  string // Rev is the git revision extracted
+	Dir  string // Dir is the temporary directory holding the files of the repository root at the revision
+	root string // root is the root directory of the work tree the revision was extracted from
+}
+
+// ParseRange parses a revision range of the form from..to, or a single revision from which is compared with the work
+// tree, in which case to is empty.
+func ParseRange(revRange string) (string, string, error) {
+	from, to, isRange := strings.Cut(revRange, "..")
+	// from...to, the symmetric difference, is not supported
+	if from == "" || (isRange && to == "") || strings.HasPrefix(to, ".") {
+		return "", "", fmt.Errorf("invalid revision range %q, expected from..to or a single revision", revRange)
+	}
+	return from, to, nil
+}
+
+// Extract extracts the files of the revision rev of the git repository holding the directory dir into a temporary
+// directory, using git archive. Only the Go modules holding the paths of the work tree are extracted, i.e. the
+// directory of the closest go.mod of each path at the revision, or the path itself if it is in no module, so that the
+// packages they import from their module are extracted too; a trailing /... of a path is ignored. All the files are extracted if no
+// path is given. The Tree must be removed with Remove once it is not needed anymore.
+func Extract(dir string, rev string, paths ...string) (*Tree, error) {
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root = strings.TrimSpace(root)
+	// A revision starting with - would be taken as an option of git
+	if strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("invalid revision %q", rev)
+	}
+	commit, err := git(root, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %s", rev)
+	}
+	commit = strings.TrimSpace(commit)
+	tree := &Tree{Rev: rev, root: root}
+
+	var pathspecs []string
+	var modules map[string]bool
+	if len(paths) != 0 {
+		if modules, err = moduleDirs(root, commit); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range paths {
+		rel, _, err := tree.relPath(p)
+		if err != nil {
+			return nil, err
+		}
+		moduleRel := moduleDir(modules, rel)
+		if moduleRel == "." {
+			pathspecs = nil
+			break
+		}
+		pathspecs = append(pathspecs, filepath.ToSlash(moduleRel))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "structogqlgen-rev-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the revision directory, error was: %v", err)
+	}
+	tree.Dir = tmpDir
+	// Run from the root, as git archive only archives the current directory of a sub-directory
+	if err := archive(root, commit, pathspecs, tmpDir); err != nil {
+		tree.Remove()
+		return nil, fmt.Errorf("failed to extract revision %s, error was: %v", rev, err)
+	}
+	return tree, nil
+}
+
+// moduleDirs returns the directories holding a go.mod file in the commit of the git repository at root, relative to
+// the root.
+func moduleDirs(root string, commit string) (map[string]bool, error) {
+	// The commit is the object name resolved by git rev-parse, which cannot be taken as an option of git
+	names, err := git(root, "ls-tree", "-r", "-z", "--name-only", commit)
+	if err != nil {
+		return nil, err
+	}
+	modules := make(map[string]bool)
+	for _, name := range strings.Split(names, "\x00") {
+		if path.Base(name) == "go.mod" {
+			modules[filepath.FromSlash(path.Dir(name))] = true
+		}
+	}
+	return modules, nil
+}
+
+// moduleDir returns the closest of the module directories holding the path rel, see moduleDirs, or the path itself if
+// none holds it.
+func moduleDir(modules map[string]bool, rel string) string {
+	for current := rel; ; current = filepath.Dir(current) {
+		if modules[current] {
+			return current
+		}
+		if current == "." {
+			return rel
+		}
+	}
+}
+
+// archive streams the files of the commit of the git repository at root matching the pathspecs, all of them if
+// pathspecs is empty, into the directory dir.
+func archive(root string, commit string, pathspecs []string, dir string) error {
+	cmd := exec.Command("git", append([]string{"archive", "--format=tar", commit, "--"}, pathspecs...)...)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	untarErr := untar(dir, stdout)
+	if untarErr != nil {
+		cmd.Process.Kill()
+	} else {
+		// Read the padding following the end of the archive, so that git does not block writing it
+		io.Copy(io.Discard, stdout)
+	}
+	if err := cmd.Wait(); err != nil && untarErr == nil {
+		return fmt.Errorf("git archive failed, error was: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return untarErr
+}
+
+// Path returns the path within the Tree of the path p of the work tree, keeping a trailing /... if any.
+func (tree *Tree) Path(p string) (string, error) {
+	rel, recursive, err := tree.relPath(p)
+	if err != nil {
+		return "", err
+	}
+	treePath := filepath.Join(tree.Dir, rel)
+	if recursive {
+		treePath += string(filepath.Separator) + "..."
+	}
+	return treePath, nil
+}
+
+// relPath returns the path p of the work tree relative to its root, without its trailing /... if any, and whether it
+// had one.
+func (tree *Tree) relPath(p string) (string, bool, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", false, err
+	}
+	suffix := string(filepath.Separator) + "..."
+	recursive := strings.HasSuffix(abs, suffix)
+	abs = strings.TrimSuffix(abs, suffix)
+	// The root is resolved by git, while p may go through a symbolic link
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(tree.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false, fmt.Errorf("%s is outside of the git repository %s", p, tree.root)
+	}
+	return rel, recursive, nil
+}
+
+// Remove removes the temporary directory of the Tree.
+func (tree *Tree) Remove() {
+	os.RemoveAll(tree.Dir)
+}
+
+// git runs the git command with the args in the directory dir and returns its standard output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed, error was: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// untar extracts the directories and regular files of the tar archive into the directory dir.
+func untar(dir string, archive io.Reader) error {
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %s in the archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, reader)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
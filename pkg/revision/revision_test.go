@@ -0,0 +1,120 @@
+package revision
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseRange is a unit test for the ParseRange function.
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		revRange string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{revRange: "main..HEAD", wantFrom: "main", wantTo: "HEAD"},
+		{revRange: "v1.0.0", wantFrom: "v1.0.0"},
+		{revRange: "main...HEAD", wantErr: true},
+		{revRange: "..HEAD", wantErr: true},
+		{revRange: "main..", wantErr: true},
+		{revRange: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.revRange, func(t *testing.T) {
+			from, to, err := ParseRange(tt.revRange)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("ParseRange() = %s, %s, want %s, %s", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+// TestExtract is a unit test for the Extract function and the Path method, extracting the revisions of a temporary repository.
+func TestExtract(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	writeModel := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Join(repo, "models"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, "models", "models.go"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q")
+	writeModel("package models\n\ntype A struct{}\n")
+	for name, content := range map[string]string{"models/go.mod": "module example.com/models\n", "other/other.go": "package other\n"} {
+		if err := os.MkdirAll(filepath.Join(repo, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("add", "-A")
+	run("commit", "-q", "-m", "first")
+	writeModel("package models\n\ntype B struct{}\n")
+	run("commit", "-q", "-a", "-m", "second")
+	// The modules are the ones of the revision, not of the work tree
+	if err := os.Rename(filepath.Join(repo, "models", "go.mod"), filepath.Join(repo, "go.mod")); err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := Extract(filepath.Join(repo, "models"), "HEAD~1", filepath.Join(repo, "models", "..."))
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	defer tree.Remove()
+
+	src, err := tree.Path(filepath.Join(repo, "models", "..."))
+	if err != nil {
+		t.Fatalf("Path() error = %v", err)
+	}
+	if want := filepath.Join(tree.Dir, "models", "..."); src != want {
+		t.Errorf("Path() = %s, want %s", src, want)
+	}
+	content, err := os.ReadFile(filepath.Join(tree.Dir, "models", "models.go"))
+	if err != nil || string(content) != "package models\n\ntype A struct{}\n" {
+		t.Errorf("Extract() extracted %q, %v, want the first revision", content, err)
+	}
+	// Only the module of the path is extracted
+	if _, err := os.Stat(filepath.Join(tree.Dir, "other")); !os.IsNotExist(err) {
+		t.Errorf("Extract() extracted the directory other outside of the module, error = %v", err)
+	}
+	if _, err := tree.Path(t.TempDir()); err == nil {
+		t.Errorf("Path() of a path outside of the repository error = nil, want an error")
+	}
+
+	all, err := Extract(repo, "HEAD")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	defer all.Remove()
+	if _, err := os.Stat(filepath.Join(all.Dir, "other", "other.go")); err != nil {
+		t.Errorf("Extract() without path did not extract all the files, error = %v", err)
+	}
+
+	for _, rev := range []string{"unknown", "--output=/tmp/archive.tar", "HEAD:models"} {
+		if _, err := Extract(repo, rev); err == nil {
+			t.Errorf("Extract() of the revision %q error = nil, want an error", rev)
+		}
+	}
+}
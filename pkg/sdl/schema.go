@@ -0,0 +1,60 @@
+package sdl
+
+import (
+	"strings"
+
+	"github.com/VintageOps/structogqlgen/pkg/conversion"
+)
+
+// ParseSchema returns the GqlSchema of the document src, e.g. to compare a schema file written earlier with a newly
+// built schema. It holds the scalars and the object and input object types, followed by their extensions, each a type
+// marked Extend, e.g. extend type Query: the extensions are kept apart from the type they extend, which the document
+// may define by hand, as a schema merged into a hand-written file does. The other definitions are left out.
+func ParseSchema(src string) (*conversion.GqlSchema, error) {
+	definitions, err := ParseDefinitions(src)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &conversion.GqlSchema{}
+	var extensions []conversion.GqlSchemaType
+	for _, definition := range definitions {
+		switch {
+		case definition.Kind == KindScalar && !definition.Extend:
+			schema.Scalars = append(schema.Scalars, conversion.GqlSchemaScalar{Name: definition.Name})
+		case definition.Kind == KindType || definition.Kind == KindInput:
+			schemaType := conversion.GqlSchemaType{Name: definition.Name, Extend: definition.Extend, Fields: schemaFields(definition.Fields)}
+			if definition.Kind == KindInput {
+				schemaType.Kind = conversion.KindInput
+			}
+			if definition.Extend {
+				extensions = append(extensions, schemaType)
+				continue
+			}
+			schema.Types = append(schema.Types, schemaType)
+		}
+	}
+	schema.Types = append(schema.Types, extensions...)
+	return schema, nil
+}
+
+// schemaFields returns the GqlSchemaField of the fields.
+func schemaFields(fields []Field) []conversion.GqlSchemaField {
+	schemaFields := make([]conversion.GqlSchemaField, 0, len(fields))
+	for _, field := range fields {
+		schemaField := conversion.GqlSchemaField{
+			Name:    field.Name,
+			Type:    strings.TrimSuffix(field.Type, "!"),
+			NonNull: strings.HasSuffix(field.Type, "!"),
+		}
+		for _, argument := range field.Arguments {
+			schemaField.Arguments = append(schemaField.Arguments, conversion.GqlSchemaArgument{
+				Name:         argument.Name,
+				Type:         argument.Type,
+				DefaultValue: argument.DefaultValue,
+			})
+		}
+		schemaFields = append(schemaFields, schemaField)
+	}
+	return schemaFields
+}
@@ -0,0 +1,399 @@
+// Package sdl provides a minimal reader of the GraphQL Schema Definition Language, locating the top-level definitions
+// of a schema document, e.g. to update the definitions generated by structogqlgen within hand-written .graphqls files.
+// See https://spec.graphql.org/October2021/#sec-Type-System
+package sdl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of the top-level definitions
+const (
+	KindSchema    = "schema"
+	KindScalar    = "scalar"
+	KindType      = "type"
+	KindInterface = "interface"
+	KindUnion     = "union"
+	KindEnum      = "enum"
+	KindInput     = "input"
+	KindDirective = "directive"
+)
+
+// definitionKinds are the keywords starting a top-level definition, besides extend.
+var definitionKinds = map[string]bool{
+	KindSchema: true, KindScalar: true, KindType: true, KindInterface: true,
+	KindUnion: true, KindEnum: true, KindInput: true, KindDirective: true,
+}
+
+// Definition represents a top-level definition of a schema document.
+type Definition struct {
+	Kind   string  // Kind is the kind of the definition, see KindType and the other kinds
+	Name   string  // Name is the name of the defined type or directive, without the @ of a directive. It is empty for a schema
+	Extend bool    // Extend is true if the definition is an extension, e.g. extend type Query
+	Start  int     // Start is the byte offset of the first token of the definition, its description excluded
+	End    int     // End is the byte offset following the last token of the definition
+	Fields []Field // Fields are the fields of an object type, interface or input object type, in the order they appear
+}
+
+// Field represents a field of a Definition.
+type Field struct {
+	Name      string     // Name is the name of the field
+	Type      string     // Type is the type of the field as written, without white spaces, e.g. [String!]!
+	Arguments []Argument // Arguments are the arguments of the field, in the order they appear
+}
+
+// Argument represents an argument of a Field.
+type Argument struct {
+	Name         string // Name is the name of the argument
+	Type         string // Type is the type of the argument as written, without white spaces, e.g. ID!
+	DefaultValue string // DefaultValue is the default value of the argument as written, empty if it has none
+}
+
+// tokenKind is the kind of a lexical token.
+type tokenKind int
+
+const (
+	tokenName tokenKind = iota
+	tokenPunct
+	tokenString
+	tokenNumber
+)
+
+// token represents a lexical token of a document, comments, white spaces and commas are ignored.
+type token struct {
+	kind  tokenKind
+	text  string
+	start int
+	end   int
+}
+
+// ParseDefinitions returns the top-level definitions of the document src, in the order they appear.
+// It returns an error if the document cannot be tokenized, or if a definition is malformed, e.g. has no name.
+func ParseDefinitions(src string) ([]Definition, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []Definition
+	for idx := 0; idx < len(tokens); {
+		tok := tokens[idx]
+		// A description is a string preceding a definition
+		if tok.kind == tokenString {
+			idx++
+			continue
+		}
+		if tok.kind != tokenName || (tok.text != "extend" && !definitionKinds[tok.text]) {
+			return nil, fmt.Errorf("%s: unexpected %q, expected a definition", position(src, tok.start), tok.text)
+		}
+
+		definition := Definition{Start: tok.start}
+		first := idx
+		if tok.text == "extend" {
+			definition.Extend = true
+			idx++
+			if idx == len(tokens) || !definitionKinds[tokens[idx].text] {
+				return nil, fmt.Errorf("%s: expected a definition kind after extend", position(src, tok.start))
+			}
+		}
+		definition.Kind = tokens[idx].text
+		idx++
+		if definition.Kind != KindSchema {
+			if definition.Kind == KindDirective && idx < len(tokens) && tokens[idx].text == "@" {
+				idx++
+			}
+			if idx == len(tokens) || tokens[idx].kind != tokenName {
+				return nil, fmt.Errorf("%s: expected the name of the %s", position(src, tok.start), definition.Kind)
+			}
+			definition.Name = tokens[idx].text
+			idx++
+		}
+
+		// The definition ends before the next definition or description found outside of any bracket
+		depth := 0
+		definition.End = tokens[idx-1].end
+		for ; idx < len(tokens); idx++ {
+			next := tokens[idx]
+			if depth == 0 && (next.kind == tokenString || (next.kind == tokenName && (next.text == "extend" || definitionKinds[next.text]))) {
+				break
+			}
+			switch next.text {
+			case "{", "(", "[":
+				if next.kind == tokenPunct {
+					depth++
+				}
+			case "}", ")", "]":
+				if next.kind == tokenPunct {
+					depth--
+				}
+			}
+			definition.End = next.end
+		}
+		if depth != 0 {
+			return nil, fmt.Errorf("%s: unbalanced brackets in the definition of %s", position(src, definition.Start), definition.Name)
+		}
+		if definition.Kind == KindType || definition.Kind == KindInterface || definition.Kind == KindInput {
+			fields, err := parseFields(src, tokens[first:idx])
+			if err != nil {
+				return nil, err
+			}
+			definition.Fields = fields
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// parseFields returns the fields of the body of the definition made of the tokens, if it has one.
+func parseFields(src string, tokens []token) ([]Field, error) {
+	// The body is the first brace found outside of the arguments of the directives
+	body, depth := -1, 0
+	for idx, tok := range tokens {
+		if tok.kind != tokenPunct {
+			continue
+		}
+		if tok.text == "(" {
+			depth++
+		} else if tok.text == ")" {
+			depth--
+		} else if tok.text == "{" && depth == 0 {
+			body = idx + 1
+			break
+		}
+	}
+	if body < 0 {
+		return nil, nil
+	}
+
+	var fields []Field
+	for idx := body; idx < len(tokens) && tokens[idx].text != "}"; {
+		if tokens[idx].kind == tokenString {
+			idx++
+			continue
+		}
+		if tokens[idx].kind != tokenName {
+			return nil, fmt.Errorf("%s: unexpected %q, expected a field", position(src, tokens[idx].start), tokens[idx].text)
+		}
+		field := Field{Name: tokens[idx].text}
+		arguments, next, err := parseArguments(src, tokens, idx+1)
+		if err != nil {
+			return nil, err
+		}
+		field.Arguments, idx = arguments, next
+		if idx == len(tokens) || tokens[idx].text != ":" {
+			return nil, fmt.Errorf("%s: expected the type of the field %s", position(src, tokens[idx-1].start), field.Name)
+		}
+		fieldType, next, err := parseTypeRef(src, tokens, idx+1)
+		if err != nil {
+			return nil, err
+		}
+		field.Type = fieldType
+		idx = next
+		// Skip the default value and the directives of the field
+		if idx < len(tokens) && tokens[idx].text == "=" {
+			idx = skipValue(tokens, idx+1)
+		}
+		for idx+1 < len(tokens) && tokens[idx].text == "@" {
+			idx = skipBalanced(tokens, idx+2, "(", ")")
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// parseArguments returns the arguments definition starting at the token idx, e.g. (first: Int = 10, after: String), and
+// the index of the token following it. It returns no argument if the token idx does not open an arguments definition.
+func parseArguments(src string, tokens []token, idx int) ([]Argument, int, error) {
+	if idx >= len(tokens) || tokens[idx].text != "(" || tokens[idx].kind != tokenPunct {
+		return nil, idx, nil
+	}
+	var arguments []Argument
+	for idx++; idx < len(tokens) && tokens[idx].text != ")"; {
+		if tokens[idx].kind == tokenString {
+			idx++
+			continue
+		}
+		if tokens[idx].kind != tokenName || idx+1 == len(tokens) || tokens[idx+1].text != ":" {
+			return nil, idx, fmt.Errorf("%s: unexpected %q, expected an argument", position(src, tokens[idx].start), tokens[idx].text)
+		}
+		argument := Argument{Name: tokens[idx].text}
+		argType, next, err := parseTypeRef(src, tokens, idx+2)
+		if err != nil {
+			return nil, idx, err
+		}
+		argument.Type, idx = argType, next
+		if idx < len(tokens) && tokens[idx].text == "=" {
+			end := skipValue(tokens, idx+1)
+			if end > len(tokens) || end == idx+1 {
+				return nil, idx, fmt.Errorf("%s: expected the default value of the argument %s", position(src, tokens[idx].start), argument.Name)
+			}
+			argument.DefaultValue = src[tokens[idx+1].start:tokens[end-1].end]
+			idx = end
+		}
+		for idx+1 < len(tokens) && tokens[idx].text == "@" {
+			idx = skipBalanced(tokens, idx+2, "(", ")")
+		}
+		arguments = append(arguments, argument)
+	}
+	if idx == len(tokens) {
+		return nil, idx, fmt.Errorf("%s: unbalanced brackets in the arguments", position(src, len(src)))
+	}
+	return arguments, idx + 1, nil
+}
+
+// parseTypeRef returns the type reference starting at the token idx, e.g. [String!]!, and the index of the token following it.
+func parseTypeRef(src string, tokens []token, idx int) (string, int, error) {
+	if idx == len(tokens) {
+		return "", idx, fmt.Errorf("%s: expected a type", position(src, len(src)))
+	}
+	var typeRef string
+	switch {
+	case tokens[idx].text == "[" && tokens[idx].kind == tokenPunct:
+		elem, next, err := parseTypeRef(src, tokens, idx+1)
+		if err != nil {
+			return "", idx, err
+		}
+		if next == len(tokens) || tokens[next].text != "]" {
+			return "", idx, fmt.Errorf("%s: expected ] closing the list type", position(src, tokens[idx].start))
+		}
+		typeRef, idx = "["+elem+"]", next+1
+	case tokens[idx].kind == tokenName:
+		typeRef, idx = tokens[idx].text, idx+1
+	default:
+		return "", idx, fmt.Errorf("%s: unexpected %q, expected a type", position(src, tokens[idx].start), tokens[idx].text)
+	}
+	if idx < len(tokens) && tokens[idx].text == "!" {
+		typeRef, idx = typeRef+"!", idx+1
+	}
+	return typeRef, idx, nil
+}
+
+// skipBalanced returns the index of the token following the tokens between open and close starting at the token idx,
+// or idx if the token idx is not open.
+func skipBalanced(tokens []token, idx int, open string, close string) int {
+	if idx >= len(tokens) || tokens[idx].text != open || tokens[idx].kind != tokenPunct {
+		return idx
+	}
+	depth := 0
+	for ; idx < len(tokens); idx++ {
+		if tokens[idx].kind != tokenPunct {
+			continue
+		}
+		if tokens[idx].text == open {
+			depth++
+		} else if tokens[idx].text == close {
+			depth--
+			if depth == 0 {
+				return idx + 1
+			}
+		}
+	}
+	return idx
+}
+
+// skipValue returns the index of the token following the value starting at the token idx.
+func skipValue(tokens []token, idx int) int {
+	if idx >= len(tokens) {
+		return idx
+	}
+	switch tokens[idx].text {
+	case "[":
+		return skipBalanced(tokens, idx, "[", "]")
+	case "{":
+		return skipBalanced(tokens, idx, "{", "}")
+	case "$":
+		return idx + 2
+	}
+	return idx + 1
+}
+
+// tokenize splits the document src into tokens.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	// Skip the byte order mark, if any
+	idx := len(src) - len(strings.TrimPrefix(src, "\uFEFF"))
+	for idx < len(src) {
+		c := src[idx]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			idx++
+		case c == '#':
+			for idx < len(src) && src[idx] != '\n' && src[idx] != '\r' {
+				idx++
+			}
+		case c == '"':
+			end, err := stringEnd(src, idx)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: src[idx:end], start: idx, end: end})
+			idx = end
+		case c == '.' && strings.HasPrefix(src[idx:], "..."):
+			tokens = append(tokens, token{kind: tokenPunct, text: "...", start: idx, end: idx + 3})
+			idx += 3
+		case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c), start: idx, end: idx + 1})
+			idx++
+		case isNameStart(c):
+			end := idx + 1
+			for end < len(src) && (isNameStart(src[end]) || isDigit(src[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenName, text: src[idx:end], start: idx, end: end})
+			idx = end
+		case c == '-' || isDigit(c):
+			end := idx + 1
+			for end < len(src) && (isDigit(src[end]) || strings.IndexByte(".eE+-", src[end]) >= 0) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[idx:end], start: idx, end: end})
+			idx = end
+		default:
+			return nil, fmt.Errorf("%s: unexpected character %q", position(src, idx), c)
+		}
+	}
+	return tokens, nil
+}
+
+// stringEnd returns the byte offset following the string, or block string, starting at the offset start of src.
+func stringEnd(src string, start int) (int, error) {
+	if strings.HasPrefix(src[start:], `"""`) {
+		for idx := start + 3; idx < len(src); idx++ {
+			if strings.HasPrefix(src[idx:], `\"""`) {
+				idx += 3
+			} else if strings.HasPrefix(src[idx:], `"""`) {
+				return idx + 3, nil
+			}
+		}
+		return 0, fmt.Errorf("%s: unterminated block string", position(src, start))
+	}
+	for idx := start + 1; idx < len(src); idx++ {
+		switch src[idx] {
+		case '\\':
+			idx++
+		case '"':
+			return idx + 1, nil
+		case '\n', '\r':
+			return 0, fmt.Errorf("%s: unterminated string", position(src, start))
+		}
+	}
+	return 0, fmt.Errorf("%s: unterminated string", position(src, start))
+}
+
+// isNameStart returns true if the character c can start a GraphQL name.
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isDigit returns true if the character c is a digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// position returns the line:column position of the byte offset of src.
+func position(src string, offset int) string {
+	line := 1 + strings.Count(src[:offset], "\n")
+	column := offset - strings.LastIndex(src[:offset], "\n")
+	return fmt.Sprintf("%d:%d", line, column)
+}
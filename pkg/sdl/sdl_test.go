@@ -0,0 +1,128 @@
+package sdl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestParseDefinitions is a unit test for the ParseDefinitions function.
+func TestParseDefinitions(t *testing.T) {
+	src := `# Hand-written schema
+"""
+The queries, with "quotes"
+"""
+type Query {
+  article(id: ID!, filter: String = "type Fake {"): Article @auth(requires: ADMIN)
+}
+
+scalar Time @specifiedBy(url: "https://example.com")
+"A description"
+extend type Article implements Node & Entity {
+  node: Node
+}
+directive @auth(requires: Role = ADMIN) on OBJECT | FIELD_DEFINITION
+union SearchResult = Article | User
+enum Role {
+  ADMIN
+  USER
+}
+schema { query: Query }
+`
+	want := []string{
+		"type Query",
+		"scalar Time",
+		"extend type Article",
+		"directive auth",
+		"union SearchResult",
+		"enum Role",
+		"schema ",
+	}
+
+	definitions, err := ParseDefinitions(src)
+	if err != nil {
+		t.Fatalf("ParseDefinitions() error = %v", err)
+	}
+	if len(definitions) != len(want) {
+		t.Fatalf("ParseDefinitions() found %d definitions, want %d", len(definitions), len(want))
+	}
+	for idx, definition := range definitions {
+		got := fmt.Sprintf("%s %s", definition.Kind, definition.Name)
+		if definition.Extend {
+			got = "extend " + got
+		}
+		if got != want[idx] {
+			t.Errorf("ParseDefinitions() definition %d = %q, want %q", idx, got, want[idx])
+		}
+		// Each definition spans from its keyword to its last token
+		text := src[definition.Start:definition.End]
+		if strings.HasPrefix(text, " ") || strings.HasSuffix(text, "\n") {
+			t.Errorf("ParseDefinitions() definition %d spans %q", idx, text)
+		}
+	}
+	if got := src[definitions[1].Start:definitions[1].End]; got != `scalar Time @specifiedBy(url: "https://example.com")` {
+		t.Errorf("ParseDefinitions() scalar spans %q", got)
+	}
+	if got := src[definitions[4].Start:definitions[4].End]; got != "union SearchResult = Article | User" {
+		t.Errorf("ParseDefinitions() union spans %q", got)
+	}
+}
+
+// TestParseDefinitionsErrors is a unit test for the errors of the ParseDefinitions function.
+func TestParseDefinitionsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "NotADefinition", src: "query { a }"},
+		{name: "NoName", src: "type {\n}"},
+		{name: "ExtendNothing", src: "extend Query"},
+		{name: "Unbalanced", src: "type A {\n  a: Int\n"},
+		{name: "UnterminatedString", src: "\"description\ntype A"},
+		{name: "UnexpectedCharacter", src: "type A { a: Int; }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseDefinitions(tt.src); err == nil {
+				t.Errorf("ParseDefinitions() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// TestParseSchema is a unit test for the ParseSchema function.
+func TestParseSchema(t *testing.T) {
+	src := `scalar BigInt
+extend type Article {
+  views: BigInt
+}
+type Article @key(fields: "id") {
+  "The identifier"
+  id: ID!
+  comments(first: Int = 10, after: String): [Comment!]! @deprecated(reason: "paginate")
+}
+input ArticleInput {
+  title: String = "untitled" @length(max: 100)
+  tags: [String]
+}
+enum Status { DRAFT }
+extend type Query {
+  article(id: ID!): Article
+}
+extend type Query {
+  me: User
+}
+`
+	// The extensions are kept apart, after the types
+	want := "scalar BigInt\n\ntype Article {\n  id: ID!\n  comments(first: Int = 10, after: String): [Comment!]!\n}\n\ninput ArticleInput {\n  title: String\n  tags: [String]\n}\n\n" +
+		"extend type Article {\n  views: BigInt\n}\n\nextend type Query {\n  article(id: ID!): Article\n}\n\nextend type Query {\n  me: User\n}\n\n"
+
+	schema, err := ParseSchema(src)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	if got := schema.String(); got != want {
+		t.Errorf("ParseSchema() = %q, want %q", got, want)
+	}
+}